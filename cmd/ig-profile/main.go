@@ -100,7 +100,7 @@ func main() {
 	if err != nil {
 		fail(err)
 	}
-	media, err := instagram.FetchUserMedia(ctx, username, profile, cookies, *maxFlag, 50)
+	media, err := instagram.FetchUserMedia(ctx, username, profile, cookies, instagram.Limit{Items: *maxFlag}, 50)
 	if err != nil {
 		if len(media) == 0 {
 			fail(err)
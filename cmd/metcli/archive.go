@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steipete/metcli/internal/archive"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// openArchive opens the archive at path, or returns nil when no --archive was
+// given.
+func openArchive(ctx context.Context, path string) (*archive.Archive, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	a, err := archive.Open(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	return a, nil
+}
+
+func archiveProfile(ctx context.Context, path string, profile instagram.Profile) error {
+	a, err := openArchive(ctx, path)
+	if err != nil || a == nil {
+		return err
+	}
+	defer a.Close()
+	if err := a.UpsertProfile(ctx, profile); err != nil {
+		return fmt.Errorf("archive profile: %w", err)
+	}
+	return nil
+}
+
+func archiveMedia(ctx context.Context, path string, media []instagram.MediaItem) error {
+	a, err := openArchive(ctx, path)
+	if err != nil || a == nil {
+		return err
+	}
+	defer a.Close()
+	if err := a.UpsertMedia(ctx, media); err != nil {
+		return fmt.Errorf("archive media: %w", err)
+	}
+	return nil
+}
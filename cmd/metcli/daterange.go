@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// RangeFlags are the --since/--until flags of the media-fetching commands.
+type RangeFlags struct {
+	Since string `help:"only media taken on or after this date (2006-01-02 or RFC 3339) or this long ago (30d, 2w, 12h)"`
+	Until string `help:"only media taken before this date (a plain date includes that day) or this long ago"`
+}
+
+// limit builds the fetch limit of a command from its caps and window.
+func (flags RangeFlags) limit(items, posts int) (instagram.Limit, error) {
+	now := time.Now()
+	since, err := parseTimeBound(flags.Since, now, false)
+	if err != nil {
+		return instagram.Limit{}, fmt.Errorf("--since: %w", err)
+	}
+	until, err := parseTimeBound(flags.Until, now, true)
+	if err != nil {
+		return instagram.Limit{}, fmt.Errorf("--until: %w", err)
+	}
+	if since > 0 && until > 0 && since >= until {
+		return instagram.Limit{}, fmt.Errorf("--since must be before --until")
+	}
+	return instagram.Limit{Items: items, Posts: posts, Since: since, Until: until}, nil
+}
+
+// parseTimeBound reads an absolute date or a duration back from now and
+// returns it in unix seconds, 0 when raw is empty. With endOfDay, a plain
+// date means the end of that day, so --until 2024-01-31 keeps January 31.
+func parseTimeBound(raw string, now time.Time, endOfDay bool) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if ago, ok := parseAgo(raw); ok {
+		return now.Add(-ago).Unix(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.Unix(), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.Unix(), nil
+	}
+	return 0, fmt.Errorf("invalid date or duration: %s", raw)
+}
+
+// parseAgo reads durations with day and week units (30d, 2w) on top of
+// those time.ParseDuration knows (12h, 90m).
+func parseAgo(raw string) (time.Duration, bool) {
+	unit := raw[len(raw)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(raw[:len(raw)-1])
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		days := n
+		if unit == 'w' {
+			days *= 7
+		}
+		return time.Duration(days) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
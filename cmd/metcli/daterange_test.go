@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	got, err := parseTimeBound("30d", now, false)
+	if err != nil || got != now.AddDate(0, 0, -30).Unix() {
+		t.Fatalf("unexpected 30d: %d %v", got, err)
+	}
+	got, err = parseTimeBound("2w", now, false)
+	if err != nil || got != now.AddDate(0, 0, -14).Unix() {
+		t.Fatalf("unexpected 2w: %d %v", got, err)
+	}
+	got, err = parseTimeBound("12h", now, false)
+	if err != nil || got != now.Add(-12*time.Hour).Unix() {
+		t.Fatalf("unexpected 12h: %d %v", got, err)
+	}
+	got, err = parseTimeBound("2024-01-02T03:04:05Z", now, false)
+	if err != nil || got != time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix() {
+		t.Fatalf("unexpected RFC 3339: %d %v", got, err)
+	}
+
+	day := time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local)
+	if got, _ := parseTimeBound("2024-01-31", now, false); got != day.Unix() {
+		t.Fatalf("expected start of day, got %d", got)
+	}
+	if got, _ := parseTimeBound("2024-01-31", now, true); got != day.AddDate(0, 0, 1).Unix() {
+		t.Fatalf("expected end of day, got %d", got)
+	}
+
+	if got, err := parseTimeBound("", now, false); got != 0 || err != nil {
+		t.Fatalf("expected open bound, got %d %v", got, err)
+	}
+	if _, err := parseTimeBound("last month", now, false); err == nil {
+		t.Fatalf("expected error for unknown input")
+	}
+}
+
+func TestRangeFlagsLimit(t *testing.T) {
+	limit, err := RangeFlags{Since: "2024-01-01", Until: "2024-01-31"}.limit(10, 2)
+	if err != nil {
+		t.Fatalf("limit: %v", err)
+	}
+	if limit.Items != 10 || limit.Posts != 2 || limit.Since == 0 || limit.Until <= limit.Since {
+		t.Fatalf("unexpected limit: %+v", limit)
+	}
+	if _, err := (RangeFlags{Since: "2024-02-01", Until: "2024-01-01"}).limit(0, 0); err == nil {
+		t.Fatalf("expected inverted range error")
+	}
+}
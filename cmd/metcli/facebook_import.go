@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steipete/metcli/internal/dyi"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputFacebookPost struct {
+	Timestamp int64        `json:"timestamp"`
+	Title     string       `json:"title,omitempty"`
+	Text      string       `json:"text,omitempty"`
+	Media     []outputItem `json:"media,omitempty"`
+	Links     []string     `json:"links,omitempty"`
+}
+
+type outputAlbum struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	UpdatedAt   int64        `json:"updated_at,omitempty"`
+	Photos      []outputItem `json:"photos"`
+}
+
+type outputFacebookComment struct {
+	Timestamp int64  `json:"timestamp"`
+	Author    string `json:"author,omitempty"`
+	Text      string `json:"text"`
+	Title     string `json:"title,omitempty"`
+}
+
+type outputFacebookReaction struct {
+	Timestamp int64  `json:"timestamp"`
+	Actor     string `json:"actor,omitempty"`
+	Reaction  string `json:"reaction"`
+	Title     string `json:"title,omitempty"`
+}
+
+type outputFriend struct {
+	Name  string `json:"name"`
+	Since int64  `json:"since,omitempty"`
+}
+
+func (cmd *FacebookImportCmd) Run() error {
+	if strings.TrimSpace(cmd.Path) == "" {
+		return fmt.Errorf("export ZIP or directory required")
+	}
+	export, err := dyi.Open(cmd.Path)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+
+	data := export.Facebook()
+	printWarnings("[metcli]", data.Warnings)
+
+	section := strings.ToLower(strings.TrimSpace(cmd.Section))
+	albums := data.Albums
+	if filter := strings.ToLower(strings.TrimSpace(cmd.Album)); filter != "" {
+		albums = albums[:0:0]
+		for _, album := range data.Albums {
+			if strings.Contains(strings.ToLower(album.Name), filter) {
+				albums = append(albums, album)
+			}
+		}
+	}
+
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if cmd.JSON {
+		format = "json"
+	}
+
+	switch section {
+	case "posts", "albums", "photos", "media":
+		if format == "json" || format == "jsonl" {
+			return cmd.writeStructured(format, section, data, albums)
+		}
+		var media []instagram.MediaItem
+		switch section {
+		case "posts":
+			for _, post := range data.Posts {
+				media = append(media, post.Media...)
+			}
+		case "albums":
+			for _, album := range albums {
+				media = append(media, album.Photos...)
+			}
+		case "photos":
+			media = data.Photos
+		default:
+			media = data.Media()
+		}
+		return cmd.writeMedia(export, data.Name, media)
+	}
+
+	// Non-media sections print JSON or plain text.
+	if format != "json" && format != "jsonl" {
+		format = "text"
+	}
+	switch section {
+	case "comments":
+		comments := firstN(data.Comments, cmd.Max)
+		if format != "text" {
+			payload := make([]outputFacebookComment, 0, len(comments))
+			for _, comment := range comments {
+				payload = append(payload, outputFacebookComment(comment))
+			}
+			return writeRecords(format, payload)
+		}
+		for _, comment := range comments {
+			_, _ = fmt.Fprintf(os.Stdout, "[%s] %s\n    %s\n", formatTimestamp(comment.Timestamp), comment.Title, comment.Text)
+		}
+		return nil
+	case "reactions":
+		reactions := firstN(data.Reactions, cmd.Max)
+		if format != "text" {
+			payload := make([]outputFacebookReaction, 0, len(reactions))
+			for _, reaction := range reactions {
+				payload = append(payload, outputFacebookReaction(reaction))
+			}
+			return writeRecords(format, payload)
+		}
+		for _, reaction := range reactions {
+			_, _ = fmt.Fprintf(os.Stdout, "[%s] %s\t%s\n", formatTimestamp(reaction.Timestamp), reaction.Reaction, reaction.Title)
+		}
+		return nil
+	case "friends":
+		friends := firstN(data.Friends, cmd.Max)
+		if format != "text" {
+			payload := make([]outputFriend, 0, len(friends))
+			for _, friend := range friends {
+				payload = append(payload, outputFriend(friend))
+			}
+			return writeRecords(format, payload)
+		}
+		for _, friend := range friends {
+			_, _ = fmt.Fprintf(os.Stdout, "%s\t%s\n", friend.Name, formatTimestamp(friend.Since))
+		}
+		return nil
+	case "messages":
+		return writeConversations(data.Threads, cmd.Thread, cmd.Max, format)
+	case "summary":
+		counts := []struct {
+			name  string
+			count int
+		}{
+			{"posts", len(data.Posts)},
+			{"albums", len(data.Albums)},
+			{"photos", len(data.Photos)},
+			{"media", len(data.Media())},
+			{"comments", len(data.Comments)},
+			{"reactions", len(data.Reactions)},
+			{"friends", len(data.Friends)},
+			{"messages", len(data.Threads)},
+		}
+		if format != "text" {
+			summary := map[string]int{}
+			for _, entry := range counts {
+				summary[entry.name] = entry.count
+			}
+			return writeJSON(summary)
+		}
+		for _, entry := range counts {
+			_, _ = fmt.Fprintf(os.Stdout, "%s\t%d\n", entry.name, entry.count)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported section: %s", cmd.Section)
+	}
+}
+
+func (cmd *FacebookImportCmd) writeStructured(format, section string, data dyi.Facebook, albums []dyi.Album) error {
+	switch section {
+	case "posts":
+		posts := firstN(data.Posts, cmd.Max)
+		payload := make([]outputFacebookPost, 0, len(posts))
+		for _, post := range posts {
+			payload = append(payload, outputFacebookPost{
+				Timestamp: post.Timestamp,
+				Title:     post.Title,
+				Text:      post.Text,
+				Media:     mediaOutput(post.Media),
+				Links:     post.Links,
+			})
+		}
+		return writeRecords(format, payload)
+	case "albums":
+		albums = firstN(albums, cmd.Max)
+		payload := make([]outputAlbum, 0, len(albums))
+		for _, album := range albums {
+			payload = append(payload, outputAlbum{
+				Name:        album.Name,
+				Description: album.Description,
+				UpdatedAt:   album.UpdatedAt,
+				Photos:      mediaOutput(album.Photos),
+			})
+		}
+		return writeRecords(format, payload)
+	case "photos":
+		return writeRecords(format, mediaOutput(firstN(data.Photos, cmd.Max)))
+	default:
+		return writeRecords(format, mediaOutput(firstN(data.Media(), cmd.Max)))
+	}
+}
+
+func (cmd *FacebookImportCmd) writeMedia(export *dyi.Export, owner string, media []instagram.MediaItem) error {
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+	// Exports carry no video thumbnails, so the inline grid only draws photos.
+	includeVideos := cmd.IncludeVideos && format != "inline"
+	items := instagram.TagItems(instagram.KindMedia, media, includeVideos)
+	items = firstN(items, cmd.Max)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no media in export")
+		return nil
+	}
+	return writeItems(format, items, owner, instagram.CookieBundle{}, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Load:      exportImageLoader(export),
+		Template:  tmpl,
+	})
+}
+
+func mediaOutput(media []instagram.MediaItem) []outputItem {
+	out := make([]outputItem, 0, len(media))
+	for _, item := range media {
+		out = append(out, toOutputItem(instagram.Item{Kind: instagram.KindMedia, MediaItem: item}))
+	}
+	return out
+}
+
+// firstN caps items at max (0 = all).
+func firstN[T any](items []T, max int) []T {
+	if max > 0 && len(items) > max {
+		return items[:max]
+	}
+	return items
+}
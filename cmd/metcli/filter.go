@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// FilterFlags are the caption filters of the media-fetching commands. All
+// given filters must match; --hashtag and --mention match any of their
+// values.
+type FilterFlags struct {
+	Grep    string   `help:"only media whose caption matches this regular expression (prefix (?i) to ignore case)"`
+	Hashtag []string `help:"only media whose caption has one of these hashtags" sep:","`
+	Mention []string `help:"only media whose caption mentions one of these accounts" sep:","`
+}
+
+// matcher compiles the filters into a predicate, nil when none is given.
+func (flags FilterFlags) matcher() (func(instagram.MediaItem) bool, error) {
+	var pattern *regexp.Regexp
+	if expr := strings.TrimSpace(flags.Grep); expr != "" {
+		compiled, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("--grep: %w", err)
+		}
+		pattern = compiled
+	}
+	hashtags := filterSet(flags.Hashtag, "#")
+	mentions := filterSet(flags.Mention, "@")
+	if pattern == nil && len(hashtags) == 0 && len(mentions) == 0 {
+		return nil, nil
+	}
+	return func(item instagram.MediaItem) bool {
+		if pattern != nil && !pattern.MatchString(item.Caption) {
+			return false
+		}
+		if len(hashtags) > 0 && !anyIn(item.Hashtags(), hashtags) {
+			return false
+		}
+		if len(mentions) > 0 && !anyIn(item.Mentions(), mentions) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// filterSet normalizes flag values the way Hashtags and Mentions report
+// them: lowercased, without the leading # or @.
+func filterSet(values []string, prefix string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), prefix))
+		if value != "" {
+			set[value] = struct{}{}
+		}
+	}
+	return set
+}
+
+func anyIn(values []string, set map[string]struct{}) bool {
+	for _, value := range values {
+		if _, ok := set[value]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaLimit builds the Limit every output path of a media command shares,
+// so --max and --max-posts count the same items in every format: the caps,
+// the --since/--until window, the caption filters and, without
+// --include-videos, a match that drops videos before they are counted. The
+// profile picture is never counted (see Limit.ApplyItems).
+func mediaLimit(rng RangeFlags, filter FilterFlags, items, posts int, includeVideos bool) (instagram.Limit, error) {
+	limit, err := rng.limit(items, posts)
+	if err != nil {
+		return instagram.Limit{}, err
+	}
+	match, err := filter.matcher()
+	if err != nil {
+		return instagram.Limit{}, err
+	}
+	if !includeVideos {
+		captions := match
+		match = func(item instagram.MediaItem) bool {
+			return !item.IsVideo && (captions == nil || captions(item))
+		}
+	}
+	limit.Match = match
+	return limit, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func TestFilterFlagsMatcher(t *testing.T) {
+	match, err := FilterFlags{}.matcher()
+	if err != nil || match != nil {
+		t.Fatalf("expected no matcher without filters, got %v", err)
+	}
+
+	match, err = FilterFlags{Grep: "(?i)summer", Hashtag: []string{"#Launch", "other"}, Mention: []string{"@Alice"}}.matcher()
+	if err != nil {
+		t.Fatalf("matcher: %v", err)
+	}
+	cases := []struct {
+		caption string
+		want    bool
+	}{
+		{"Summer drop #launch with @alice", true},
+		{"Summer drop #launch", false},
+		{"Winter drop #launch with @alice", false},
+		{"summer #other @ALICE", true},
+	}
+	for _, tc := range cases {
+		if got := match(instagram.MediaItem{Caption: tc.caption}); got != tc.want {
+			t.Fatalf("%q: expected %v, got %v", tc.caption, tc.want, got)
+		}
+	}
+
+	if _, err := (FilterFlags{Grep: "("}).matcher(); err == nil {
+		t.Fatalf("expected invalid regexp error")
+	}
+}
+
+func TestMediaLimitCountsOnlyKeptMedia(t *testing.T) {
+	limit, err := mediaLimit(RangeFlags{}, FilterFlags{Grep: "keep"}, 2, 0, false)
+	if err != nil {
+		t.Fatalf("mediaLimit: %v", err)
+	}
+	items := []instagram.Item{
+		{Kind: instagram.KindAvatar, MediaItem: instagram.MediaItem{URL: "avatar"}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: "v", Caption: "keep", IsVideo: true}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: "a", Caption: "keep"}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: "b", Caption: "skip"}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: "c", Caption: "keep"}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: "d", Caption: "keep"}},
+	}
+	var got []string
+	for _, item := range limit.ApplyItems(items) {
+		got = append(got, item.URL)
+	}
+	if len(got) != 3 || got[0] != "avatar" || got[1] != "a" || got[2] != "c" {
+		t.Fatalf("expected avatar, a, c, got %v", got)
+	}
+
+	if limit, _ = mediaLimit(RangeFlags{}, FilterFlags{}, 0, 0, true); limit.Match != nil {
+		t.Fatalf("expected no match with videos and without filters")
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steipete/metcli/internal/dyi"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func (cmd *ImportInstagramDYICmd) Run() error {
+	if strings.TrimSpace(cmd.Path) == "" {
+		return fmt.Errorf("export ZIP or directory required")
+	}
+	export, err := dyi.Open(cmd.Path)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+
+	data := export.Instagram()
+	printWarnings("[metcli]", data.Warnings)
+
+	section := strings.ToLower(strings.TrimSpace(cmd.Section))
+	switch section {
+	case "posts", "stories", "reels", "media":
+		return cmd.writeMedia(export, data, section)
+	}
+
+	// Non-media sections print JSON or plain text.
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if cmd.JSON {
+		format = "json"
+	}
+	if format != "json" && format != "jsonl" {
+		format = "text"
+	}
+
+	switch section {
+	case "liked":
+		liked := data.Liked
+		if cmd.Max > 0 && len(liked) > cmd.Max {
+			liked = liked[:cmd.Max]
+		}
+		if format != "text" {
+			// Likes carry no image URL, so they bypass TagItems.
+			payload := make([]outputItem, 0, len(liked))
+			for _, media := range liked {
+				payload = append(payload, toOutputItem(instagram.Item{Kind: instagram.KindMedia, MediaItem: media}))
+			}
+			return writeRecords(format, payload)
+		}
+		for _, item := range liked {
+			_, _ = fmt.Fprintf(os.Stdout, "https://www.instagram.com/p/%s/\t@%s\n", item.Shortcode, item.Username)
+		}
+		return nil
+	case "comments":
+		comments := data.Comments
+		if cmd.Max > 0 && len(comments) > cmd.Max {
+			comments = comments[:cmd.Max]
+		}
+		return writeComments(os.Stdout, format, comments)
+	case "followers", "following":
+		accounts := data.Followers
+		if section == "following" {
+			accounts = data.Following
+		}
+		if cmd.Max > 0 && len(accounts) > cmd.Max {
+			accounts = accounts[:cmd.Max]
+		}
+		if format == "text" {
+			format = "csv"
+		}
+		return writeAccounts(os.Stdout, format, accounts)
+	case "messages":
+		return writeConversations(data.Threads, cmd.Thread, cmd.Max, format)
+	case "summary":
+		summary := map[string]int{
+			"posts":     len(data.Posts),
+			"stories":   len(data.Stories),
+			"reels":     len(data.Reels),
+			"liked":     len(data.Liked),
+			"comments":  len(data.Comments),
+			"followers": len(data.Followers),
+			"following": len(data.Following),
+			"messages":  len(data.Threads),
+		}
+		if format != "text" {
+			return writeJSON(summary)
+		}
+		for _, name := range []string{"posts", "stories", "reels", "liked", "comments", "followers", "following", "messages"} {
+			_, _ = fmt.Fprintf(os.Stdout, "%s\t%d\n", name, summary[name])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported section: %s", cmd.Section)
+	}
+}
+
+func (cmd *ImportInstagramDYICmd) writeMedia(export *dyi.Export, data dyi.Instagram, section string) error {
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+
+	// Exports carry no video thumbnails, so the inline grid only draws photos.
+	includeVideos := cmd.IncludeVideos && format != "inline"
+	var items []instagram.Item
+	if section == "posts" || section == "media" {
+		items = append(items, instagram.TagItems(instagram.KindMedia, data.Posts, includeVideos)...)
+	}
+	if section == "reels" || section == "media" {
+		items = append(items, instagram.TagItems(instagram.KindMedia, data.Reels, includeVideos)...)
+	}
+	if section == "stories" || section == "media" {
+		items = append(items, instagram.TagItems(instagram.KindStory, data.Stories, includeVideos)...)
+	}
+	if cmd.Max > 0 && len(items) > cmd.Max {
+		items = items[:cmd.Max]
+	}
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no media in export")
+		return nil
+	}
+
+	return writeItems(format, items, data.Username, instagram.CookieBundle{}, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Load:      exportImageLoader(export),
+		Template:  tmpl,
+	})
+}
+
+// exportImageLoader reads item images from a DYI export instead of the CDN.
+func exportImageLoader(export *dyi.Export) imageLoader {
+	return func(_ context.Context, item instagram.Item) ([]byte, error) {
+		return export.ReadFile(item.URL)
+	}
+}
+
+// writeConversations lists the threads of an export, or prints the
+// transcripts of those whose id or title contains filter. max keeps the
+// newest messages of each transcript.
+func writeConversations(threads []dyi.Conversation, filter string, max int, format string) error {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" {
+		list := make([]instagram.Thread, 0, len(threads))
+		for _, conv := range threads {
+			list = append(list, conv.Thread)
+		}
+		return writeInbox(os.Stdout, format, firstN(list, max))
+	}
+
+	for _, conv := range threads {
+		if !strings.Contains(strings.ToLower(conv.Thread.ID), filter) &&
+			!strings.Contains(strings.ToLower(conv.Thread.Title), filter) {
+			continue
+		}
+		messages := conv.Messages
+		if max > 0 && len(messages) > max {
+			messages = messages[len(messages)-max:]
+		}
+		if err := writeTranscript(os.Stdout, format, conv.Thread, messages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(payload any) error {
+	encoded, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(os.Stdout, string(encoded))
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputComment struct {
+	ID         string          `json:"id"`
+	ParentID   string          `json:"parent_id,omitempty"`
+	Username   string          `json:"username"`
+	Text       string          `json:"text"`
+	CreatedAt  int64           `json:"created_at"`
+	LikeCount  int             `json:"like_count"`
+	ReplyCount int             `json:"reply_count,omitempty"`
+	Replies    []outputComment `json:"replies,omitempty"`
+	MediaOwner string          `json:"media_owner,omitempty"`
+}
+
+func (cmd *InstagramCommentsCmd) Run() error {
+	shortcode := instagram.ParseShortcode(cmd.Post)
+	if shortcode == "" {
+		return fmt.Errorf("shortcode or post URL required")
+	}
+	mediaID, err := instagram.ShortcodeToMediaID(shortcode)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if cmd.JSON {
+		format = "json"
+	}
+	if format != "text" && format != "json" && format != "jsonl" {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	comments, err := instagram.FetchComments(ctx, mediaID, cookies, cmd.Max, cmd.Replies)
+	if err != nil {
+		if len(comments) == 0 {
+			return err
+		}
+		printWarnings("[metcli]", []string{fmt.Sprintf("comments warning: %s", err.Error())})
+	}
+	if len(comments) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no comments")
+		return nil
+	}
+
+	return writeComments(os.Stdout, format, comments)
+}
+
+func writeComments(w io.Writer, format string, comments []instagram.Comment) error {
+	switch format {
+	case "json":
+		payload := make([]outputComment, 0, len(comments))
+		for _, comment := range comments {
+			payload = append(payload, toOutputComment(comment))
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, string(encoded))
+	case "jsonl":
+		// One comment per line; replies follow their parent and point back
+		// to it through parent_id.
+		enc := json.NewEncoder(w)
+		for _, comment := range comments {
+			flat := toOutputComment(comment)
+			flat.Replies = nil
+			if err := enc.Encode(flat); err != nil {
+				return err
+			}
+			for _, reply := range comment.Replies {
+				if err := enc.Encode(toOutputComment(reply)); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		for _, comment := range comments {
+			writeCommentText(w, comment, "")
+			for _, reply := range comment.Replies {
+				writeCommentText(w, reply, "  ")
+			}
+		}
+	}
+	return nil
+}
+
+func writeCommentText(w io.Writer, comment instagram.Comment, indent string) {
+	meta := time.Unix(comment.CreatedAt, 0).Format("2006-01-02 15:04")
+	if comment.LikeCount > 0 {
+		meta += fmt.Sprintf(", %d likes", comment.LikeCount)
+	}
+	if comment.MediaOwner != "" {
+		meta += ", on @" + comment.MediaOwner
+	}
+	text := strings.ReplaceAll(strings.TrimSpace(comment.Text), "\n", "\n"+indent+"  ")
+	_, _ = fmt.Fprintf(w, "%s@%s (%s): %s\n", indent, comment.Username, meta, text)
+}
+
+func toOutputComment(comment instagram.Comment) outputComment {
+	out := outputComment{
+		ID:         comment.ID,
+		ParentID:   comment.ParentID,
+		Username:   comment.Username,
+		Text:       comment.Text,
+		CreatedAt:  comment.CreatedAt,
+		LikeCount:  comment.LikeCount,
+		ReplyCount: comment.ReplyCount,
+		MediaOwner: comment.MediaOwner,
+	}
+	for _, reply := range comment.Replies {
+		out.Replies = append(out.Replies, toOutputComment(reply))
+	}
+	return out
+}
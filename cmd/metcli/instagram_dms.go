@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputThread struct {
+	ID             string          `json:"id"`
+	Title          string          `json:"title,omitempty"`
+	IsGroup        bool            `json:"is_group"`
+	LastActivityAt int64           `json:"last_activity_at,omitempty"`
+	Participants   []outputAccount `json:"participants"`
+}
+
+type outputMessage struct {
+	ID         string           `json:"id"`
+	ThreadID   string           `json:"thread_id,omitempty"`
+	SenderID   string           `json:"sender_id,omitempty"`
+	Sender     string           `json:"sender,omitempty"`
+	FromViewer bool             `json:"from_viewer"`
+	Timestamp  int64            `json:"timestamp"`
+	Type       string           `json:"type"`
+	Text       string           `json:"text,omitempty"`
+	LinkURL    string           `json:"link_url,omitempty"`
+	Media      []outputItem     `json:"media,omitempty"`
+	Reactions  []outputReaction `json:"reactions,omitempty"`
+}
+
+type outputReaction struct {
+	SenderID string `json:"sender_id,omitempty"`
+	Sender   string `json:"sender,omitempty"`
+	Emoji    string `json:"emoji"`
+}
+
+type outputTranscript struct {
+	Thread   outputThread    `json:"thread"`
+	Messages []outputMessage `json:"messages"`
+}
+
+func (cmd *InstagramDMsCmd) Run() error {
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if cmd.JSON {
+		format = "json"
+	}
+	if format != "text" && format != "json" && format != "jsonl" {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	threadID := strings.TrimSpace(cmd.Thread)
+	if threadID == "" {
+		threads, err := instagram.FetchInbox(ctx, cookies, cmd.Max)
+		if err != nil {
+			if len(threads) == 0 {
+				return err
+			}
+			printWarnings("[metcli]", []string{fmt.Sprintf("inbox warning: %s", err.Error())})
+		}
+		return writeInbox(os.Stdout, format, threads)
+	}
+
+	thread, messages, err := instagram.FetchThreadMessages(ctx, threadID, cookies, cmd.Max)
+	if err != nil {
+		if len(messages) == 0 {
+			return err
+		}
+		printWarnings("[metcli]", []string{fmt.Sprintf("thread warning: %s", err.Error())})
+	}
+	return writeTranscript(os.Stdout, format, thread, messages)
+}
+
+func writeInbox(w io.Writer, format string, threads []instagram.Thread) error {
+	if format == "jsonl" {
+		enc := json.NewEncoder(w)
+		for _, thread := range threads {
+			if err := enc.Encode(toOutputThread(thread)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if format == "json" {
+		payload := make([]outputThread, 0, len(threads))
+		for _, thread := range threads {
+			payload = append(payload, toOutputThread(thread))
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, string(encoded))
+		return nil
+	}
+	for _, thread := range threads {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", thread.ID, formatTimestamp(thread.LastActivityAt), threadLabel(thread))
+	}
+	return nil
+}
+
+func writeTranscript(w io.Writer, format string, thread instagram.Thread, messages []instagram.Message) error {
+	if format == "jsonl" {
+		// One message per line; thread_id ties them together.
+		enc := json.NewEncoder(w)
+		for _, msg := range messages {
+			if err := enc.Encode(toOutputMessage(msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if format == "json" {
+		payload := outputTranscript{
+			Thread:   toOutputThread(thread),
+			Messages: make([]outputMessage, 0, len(messages)),
+		}
+		for _, msg := range messages {
+			payload.Messages = append(payload.Messages, toOutputMessage(msg))
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, string(encoded))
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "# %s\n\n", threadLabel(thread))
+	for _, msg := range messages {
+		sender := msg.Sender
+		if msg.FromViewer {
+			sender = "me"
+		} else if sender == "" {
+			sender = msg.SenderID
+		}
+		text := strings.ReplaceAll(strings.TrimSpace(msg.Text), "\n", "\n    ")
+		if text == "" {
+			text = "[" + msg.Type + "]"
+		}
+		_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", formatTimestamp(msg.Timestamp), sender, text)
+		if msg.LinkURL != "" {
+			_, _ = fmt.Fprintf(w, "    link: %s\n", msg.LinkURL)
+		}
+		for _, media := range msg.Media {
+			if media.Shortcode != "" {
+				_, _ = fmt.Fprintf(w, "    post: https://www.instagram.com/p/%s/ by @%s\n", media.Shortcode, media.Username)
+				break
+			}
+			_, _ = fmt.Fprintf(w, "    media: %s\n", media.DownloadURL())
+		}
+		if len(msg.Reactions) > 0 {
+			parts := make([]string, 0, len(msg.Reactions))
+			for _, reaction := range msg.Reactions {
+				who := reaction.Sender
+				if who == "" {
+					who = reaction.SenderID
+				}
+				parts = append(parts, reaction.Emoji+" "+who)
+			}
+			_, _ = fmt.Fprintf(w, "    reactions: %s\n", strings.Join(parts, ", "))
+		}
+	}
+	return nil
+}
+
+// threadLabel names a thread by its title, or its participants when it has
+// none.
+func threadLabel(thread instagram.Thread) string {
+	if thread.Title != "" {
+		return thread.Title
+	}
+	names := make([]string, 0, len(thread.Participants))
+	for _, user := range thread.Participants {
+		if user.Username == "" {
+			names = append(names, user.FullName)
+			continue
+		}
+		names = append(names, "@"+user.Username)
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatTimestamp(unix int64) string {
+	if unix <= 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).Format("2006-01-02 15:04")
+}
+
+func toOutputThread(thread instagram.Thread) outputThread {
+	out := outputThread{
+		ID:             thread.ID,
+		Title:          thread.Title,
+		IsGroup:        thread.IsGroup,
+		LastActivityAt: thread.LastActivityAt,
+		Participants:   make([]outputAccount, 0, len(thread.Participants)),
+	}
+	for _, user := range thread.Participants {
+		out.Participants = append(out.Participants, toOutputAccount(user))
+	}
+	return out
+}
+
+func toOutputMessage(msg instagram.Message) outputMessage {
+	out := outputMessage{
+		ID:         msg.ID,
+		ThreadID:   msg.ThreadID,
+		SenderID:   msg.SenderID,
+		Sender:     msg.Sender,
+		FromViewer: msg.FromViewer,
+		Timestamp:  msg.Timestamp,
+		Type:       msg.Type,
+		Text:       msg.Text,
+		LinkURL:    msg.LinkURL,
+	}
+	for _, media := range msg.Media {
+		out.Media = append(out.Media, toOutputItem(instagram.Item{Kind: instagram.KindMedia, MediaItem: media}))
+	}
+	for _, reaction := range msg.Reactions {
+		out.Reactions = append(out.Reactions, outputReaction{
+			SenderID: reaction.SenderID,
+			Sender:   reaction.Sender,
+			Emoji:    reaction.Emoji,
+		})
+	}
+	return out
+}
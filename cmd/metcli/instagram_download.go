@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steipete/metcli/internal/archive"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func (cmd *InstagramDownloadCmd) Run() error {
+	source := strings.ToLower(strings.TrimSpace(cmd.Source))
+	username := instagram.ParseUsername(cmd.User)
+	// Without a user, home downloads the timeline and stories the story tray.
+	if username == "" && source != "home" && source != "stories" {
+		return fmt.Errorf("username or profile URL required")
+	}
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	store, err := openArchive(ctx, cmd.Archive)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer store.Close()
+	}
+	if cmd.Sync {
+		if source != "api" {
+			return fmt.Errorf("--sync requires --source api")
+		}
+		if match, _ := cmd.FilterFlags.matcher(); limit.Since > 0 || limit.Until > 0 || match != nil {
+			return fmt.Errorf("--sync cannot be combined with --since, --until or caption filters")
+		}
+		return cmd.runSync(ctx, username, store)
+	}
+
+	var (
+		cookies  instagram.CookieBundle
+		items    []instagram.Item
+		warnings []string
+	)
+	switch source {
+	case "stories":
+		cookies, items, warnings, err = loadStoryItems(
+			ctx,
+			username,
+			cmd.Profile,
+			cmd.Names,
+			cmd.Max,
+			cmd.IncludeVideos,
+		)
+	case "highlights":
+		cookies, items, warnings, err = loadHighlightItems(
+			ctx,
+			username,
+			"",
+			cmd.Profile,
+			cmd.Names,
+			cmd.Max,
+			cmd.IncludeVideos,
+		)
+	case "home":
+		cookies, items, warnings, err = loadHomeItems(
+			ctx,
+			cmd.Profile,
+			cmd.Names,
+			cmd.PageSize,
+			limit,
+			cmd.IncludeVideos,
+			cmd.Archive,
+		)
+	default:
+		cookies, items, warnings, err = loadInstagramItems(
+			ctx,
+			username,
+			cmd.Profile,
+			cmd.Names,
+			source,
+			cmd.PageSize,
+			limit,
+			cmd.Avatar,
+			cmd.IncludeVideos,
+			cmd.Archive,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	// Story loaders only take an item cap, so the post cap and date window
+	// are applied here; every story is a post of its own.
+	items = limit.ApplyItems(items)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no media to download")
+		return nil
+	}
+
+	client := instagram.DownloadClient()
+	saved, skipped, failed := 0, 0, 0
+	for _, item := range items {
+		account := username
+		if account == "" {
+			account = item.Username
+		}
+		path, ok, err := cmd.saveItem(ctx, client, store, item, account, cookies)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
+			failed++
+			continue
+		}
+		if !ok {
+			skipped++
+			continue
+		}
+		_, _ = fmt.Fprintln(os.Stdout, path)
+		saved++
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "[metcli] saved %d, skipped %d, failed %d\n", saved, skipped, failed)
+	if saved == 0 && failed > 0 {
+		return fmt.Errorf("all downloads failed")
+	}
+	return nil
+}
+
+// runSync downloads only what is new since the state stored in the account
+// directory, saving the state after every page so a crash can resume.
+func (cmd *InstagramDownloadCmd) runSync(ctx context.Context, username string, store *archive.Archive) error {
+	names := parseNames(cmd.Names)
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, names)
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	profile, err := instagram.FetchProfile(ctx, username, cookies)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(profile.UserID) == "" {
+		return fmt.Errorf("no user id for %s", username)
+	}
+	if store != nil {
+		if err := store.UpsertProfile(ctx, instagram.Profile{
+			Username:        profile.Username,
+			UserID:          profile.UserID,
+			ProfilePicURL:   profile.ProfilePicURL,
+			ProfilePicURLHD: profile.ProfilePicURLHD,
+		}); err != nil {
+			return fmt.Errorf("archive profile: %w", err)
+		}
+	}
+
+	dir := accountDir(cmd.Out, username)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	statePath := filepath.Join(dir, syncStateFile)
+	state, err := instagram.LoadSyncState(statePath)
+	if err != nil {
+		return fmt.Errorf("read sync state: %w", err)
+	}
+	if state.Cursor != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "[metcli] resuming interrupted sync of %s\n", username)
+	}
+
+	client := instagram.DownloadClient()
+	saved, skipped, failed := 0, 0, 0
+	save := func(items []instagram.Item) int {
+		pageFailed := 0
+		for _, item := range items {
+			path, ok, err := cmd.saveItem(ctx, client, store, item, username, cookies)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
+				failed++
+				pageFailed++
+				continue
+			}
+			if !ok {
+				skipped++
+				continue
+			}
+			_, _ = fmt.Fprintln(os.Stdout, path)
+			saved++
+		}
+		return pageFailed
+	}
+
+	save(instagram.BuildItems(instagram.Profile{
+		ProfilePicURL:   profile.ProfilePicURL,
+		ProfilePicURLHD: profile.ProfilePicURLHD,
+	}, cmd.Avatar, cmd.IncludeVideos))
+	state, err = instagram.SyncUserMedia(ctx, username, profile.UserID, cookies, state, cmd.PageSize, func(media []instagram.MediaItem, next instagram.SyncState) error {
+		if store != nil {
+			if err := store.UpsertMedia(ctx, media); err != nil {
+				return fmt.Errorf("archive media: %w", err)
+			}
+		}
+		// Keep the previous state when a file failed so the next run retries it.
+		if n := save(instagram.BuildItems(instagram.Profile{Media: media}, false, cmd.IncludeVideos)); n > 0 {
+			return fmt.Errorf("%d downloads failed", n)
+		}
+		return instagram.SaveSyncState(statePath, next)
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "[metcli] saved %d, skipped %d, failed %d\n", saved, skipped, failed)
+		return fmt.Errorf("sync interrupted (rerun to resume): %w", err)
+	}
+	if err := instagram.SaveSyncState(statePath, state); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	if state.Cursor != "" {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] page limit reached; rerun to continue")
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "[metcli] saved %d, skipped %d, failed %d\n", saved, skipped, failed)
+	return nil
+}
+
+// saveItem writes item (and its sidecar) below <out>/<account>/. It reports
+// false when the file already existed and --force was not given. Only a
+// failed download is an error: once the file is on disk, a sidecar or
+// archive write that fails is printed as a warning.
+func (cmd *InstagramDownloadCmd) saveItem(
+	ctx context.Context,
+	client *http.Client,
+	store *archive.Archive,
+	item instagram.Item,
+	account string,
+	cookies instagram.CookieBundle,
+) (string, bool, error) {
+	dir := accountDir(cmd.Out, account)
+	switch {
+	case item.Highlight != "":
+		dir = filepath.Join(dir, "highlights", instagram.SanitizeFileName(item.Highlight))
+	case item.TaggedAccount != "":
+		dir = filepath.Join(dir, "tagged")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, err
+	}
+	dest := filepath.Join(dir, instagram.MediaFileName(item))
+	if !cmd.Force {
+		if info, err := os.Stat(dest); err == nil {
+			warnSaved(markDownloaded(ctx, store, item, dest, info.Size()))
+			return dest, false, nil
+		}
+	}
+
+	written, err := instagram.SaveMedia(ctx, client, item.DownloadURL(), account, cookies, dest)
+	if err != nil {
+		return "", false, err
+	}
+	if cmd.Sidecar {
+		if err := writeSidecar(dest, item); err != nil {
+			warnSaved(fmt.Errorf("write sidecar: %w", err))
+		}
+	}
+	warnSaved(markDownloaded(ctx, store, item, dest, written))
+	return dest, true, nil
+}
+
+func warnSaved(err error) {
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "[metcli] warning: %s\n", err.Error())
+	}
+}
+
+func markDownloaded(ctx context.Context, store *archive.Archive, item instagram.Item, path string, size int64) error {
+	if store == nil || item.Kind == instagram.KindAvatar {
+		return nil
+	}
+	if err := store.MarkDownloaded(ctx, item.MediaItem, path, size); err != nil {
+		return fmt.Errorf("archive download: %w", err)
+	}
+	return nil
+}
+
+const syncStateFile = ".metcli-sync.json"
+
+func accountDir(out, account string) string {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		account = "unknown"
+	}
+	return filepath.Join(out, filepath.Base(account))
+}
+
+func writeSidecar(mediaPath string, item instagram.Item) error {
+	encoded, err := json.MarshalIndent(toOutputItem(item), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(mediaPath+".json", append(encoded, '\n'), 0o644)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func TestSaveItemTreatsSidecarFailureAsWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	defer server.Close()
+
+	out := t.TempDir()
+	cmd := &InstagramDownloadCmd{Out: out, Sidecar: true}
+	item := instagram.Item{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{URL: server.URL + "/a.jpg", Shortcode: "ABC"}}
+	dest := filepath.Join(accountDir(out, "alice"), instagram.MediaFileName(item))
+	// A directory where the sidecar belongs makes writing it fail.
+	if err := os.MkdirAll(dest+".json", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, saved, err := cmd.saveItem(context.Background(), server.Client(), nil, item, "alice", instagram.CookieBundle{})
+	if err != nil || !saved || path != dest {
+		t.Fatalf("expected %s saved despite the sidecar, got %q, %v, %v", dest, path, saved, err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "jpeg" {
+		t.Fatalf("unexpected download: %q, %v", data, err)
+	}
+
+	path, saved, err = cmd.saveItem(context.Background(), server.Client(), nil, item, "alice", instagram.CookieBundle{})
+	if err != nil || saved || path != dest {
+		t.Fatalf("expected the existing file to be skipped, got %q, %v, %v", path, saved, err)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputAccount struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	FullName      string `json:"full_name,omitempty"`
+	IsPrivate     bool   `json:"is_private"`
+	IsVerified    bool   `json:"is_verified"`
+	ProfilePicURL string `json:"profile_pic_url,omitempty"`
+}
+
+func (cmd *InstagramFollowersCmd) Run() error {
+	return runFriendships(
+		instagram.FriendshipFollowers,
+		cmd.User,
+		cmd.Format,
+		cmd.Max,
+		cmd.PageSize,
+		cmd.Profile,
+		cmd.Names,
+	)
+}
+
+func (cmd *InstagramFollowingCmd) Run() error {
+	return runFriendships(
+		instagram.FriendshipFollowing,
+		cmd.User,
+		cmd.Format,
+		cmd.Max,
+		cmd.PageSize,
+		cmd.Profile,
+		cmd.Names,
+	)
+}
+
+func runFriendships(
+	list string,
+	user string,
+	format string,
+	max int,
+	pageSize int,
+	profilePath string,
+	namesRaw string,
+) error {
+	username := instagram.ParseUsername(user)
+	if username == "" {
+		return fmt.Errorf("username or profile URL required")
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "json" && format != "jsonl" && format != "csv" {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, parseNames(namesRaw))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	profile, err := instagram.FetchProfile(ctx, username, cookies)
+	if err != nil {
+		return err
+	}
+	accounts, err := instagram.FetchFriendships(ctx, username, profile.UserID, list, cookies, max, pageSize)
+	if err != nil {
+		if len(accounts) == 0 {
+			return err
+		}
+		printWarnings("[metcli]", []string{fmt.Sprintf("%s warning: %s", list, err.Error())})
+	}
+
+	return writeAccounts(os.Stdout, format, accounts)
+}
+
+func writeAccounts(w io.Writer, format string, accounts []instagram.Account) error {
+	switch format {
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, account := range accounts {
+			if err := enc.Encode(toOutputAccount(account)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		out := csv.NewWriter(w)
+		_ = out.Write([]string{"id", "username", "full_name", "is_private", "is_verified", "profile_pic_url"})
+		for _, account := range accounts {
+			_ = out.Write([]string{
+				account.ID,
+				account.Username,
+				account.FullName,
+				strconv.FormatBool(account.IsPrivate),
+				strconv.FormatBool(account.IsVerified),
+				account.ProfilePicURL,
+			})
+		}
+		out.Flush()
+		return out.Error()
+	default:
+		payload := make([]outputAccount, 0, len(accounts))
+		for _, account := range accounts {
+			payload = append(payload, toOutputAccount(account))
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, string(encoded))
+		return nil
+	}
+}
+
+func toOutputAccount(account instagram.Account) outputAccount {
+	return outputAccount{
+		ID:            account.ID,
+		Username:      account.Username,
+		FullName:      account.FullName,
+		IsPrivate:     account.IsPrivate,
+		IsVerified:    account.IsVerified,
+		ProfilePicURL: account.ProfilePicURL,
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputHighlight struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	CoverURL   string `json:"cover_url,omitempty"`
+	MediaCount int    `json:"media_count"`
+}
+
+func (cmd *InstagramHighlightsCmd) Run() error {
+	username := instagram.ParseUsername(cmd.User)
+	if username == "" {
+		return fmt.Errorf("username or profile URL required")
+	}
+
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+	grid := gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Template:  tmpl,
+	}
+
+	ctx := context.Background()
+	if cmd.Expand {
+		cookies, items, warnings, err := loadHighlightItems(
+			ctx,
+			username,
+			cmd.Title,
+			cmd.Profile,
+			cmd.Names,
+			cmd.Max,
+			cmd.IncludeVideos,
+		)
+		if err != nil {
+			return err
+		}
+		printWarnings("[metcli]", warnings)
+		if len(items) == 0 {
+			_, _ = fmt.Fprintln(os.Stderr, "[metcli] no highlight items")
+			return nil
+		}
+		return writeItems(format, items, username, cookies, grid)
+	}
+
+	cookies, highlights, warnings, err := loadHighlights(ctx, username, cmd.Title, cmd.Profile, cmd.Names)
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	if cmd.Max > 0 && len(highlights) > cmd.Max {
+		highlights = highlights[:cmd.Max]
+	}
+	if len(highlights) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no highlights")
+		return nil
+	}
+
+	if format == "json" || format == "jsonl" {
+		payload := make([]outputHighlight, 0, len(highlights))
+		for _, highlight := range highlights {
+			payload = append(payload, outputHighlight{
+				ID:         highlight.ID,
+				Title:      highlight.Title,
+				CoverURL:   highlight.CoverURL,
+				MediaCount: highlight.MediaCount,
+			})
+		}
+		return writeRecords(format, payload)
+	}
+
+	covers := make([]instagram.Item, 0, len(highlights))
+	for _, highlight := range highlights {
+		if highlight.CoverURL == "" {
+			continue
+		}
+		covers = append(covers, instagram.Item{
+			Kind: instagram.KindHighlightCover,
+			MediaItem: instagram.MediaItem{
+				URL:       highlight.CoverURL,
+				Username:  username,
+				Highlight: highlight.Title,
+			},
+		})
+	}
+	return writeItems(format, covers, username, cookies, grid)
+}
+
+// loadHighlights lists the highlights of username, keeping only those whose
+// title contains titleFilter when one is given.
+func loadHighlights(
+	ctx context.Context,
+	username string,
+	titleFilter string,
+	profilePath string,
+	namesRaw string,
+) (instagram.CookieBundle, []instagram.Highlight, []string, error) {
+	names := parseNames(namesRaw)
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+	profile, err := instagram.FetchProfile(ctx, username, cookies)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+	highlights, err := instagram.FetchHighlights(ctx, username, profile.UserID, cookies)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	titleFilter = strings.ToLower(strings.TrimSpace(titleFilter))
+	if titleFilter == "" {
+		return cookies, highlights, warnings, nil
+	}
+	filtered := highlights[:0]
+	for _, highlight := range highlights {
+		if strings.Contains(strings.ToLower(highlight.Title), titleFilter) {
+			filtered = append(filtered, highlight)
+		}
+	}
+	return cookies, filtered, warnings, nil
+}
+
+func loadHighlightItems(
+	ctx context.Context,
+	username string,
+	titleFilter string,
+	profilePath string,
+	namesRaw string,
+	max int,
+	includeVideos bool,
+) (instagram.CookieBundle, []instagram.Item, []string, error) {
+	cookies, highlights, warnings, err := loadHighlights(ctx, username, titleFilter, profilePath, namesRaw)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	limit, err := mediaLimit(RangeFlags{}, FilterFlags{}, max, 0, includeVideos)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+	media, err := instagram.FetchHighlightMedia(ctx, username, highlights, cookies, limit)
+	if err != nil {
+		if len(media) == 0 {
+			return cookies, nil, warnings, err
+		}
+		warnings = append(warnings, fmt.Sprintf("highlights warning: %s", err.Error()))
+	}
+
+	items := instagram.TagItems(instagram.KindHighlight, media, includeVideos)
+	return cookies, items, warnings, nil
+}
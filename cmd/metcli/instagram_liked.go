@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func (cmd *InstagramLikedCmd) Run() error {
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cookies, items, warnings, err := loadLikedItems(
+		ctx,
+		cmd.Profile,
+		cmd.Names,
+		limit,
+		cmd.IncludeVideos,
+		cmd.Archive,
+	)
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no liked posts")
+		return nil
+	}
+
+	return writeItems(format, items, "", cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Group:     cmd.Group,
+		Template:  tmpl,
+	})
+}
+
+func loadLikedItems(
+	ctx context.Context,
+	profilePath string,
+	namesRaw string,
+	limit instagram.Limit,
+	includeVideos bool,
+	archivePath string,
+) (instagram.CookieBundle, []instagram.Item, []string, error) {
+	names := parseNames(namesRaw)
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	media, err := instagram.FetchLikedMedia(ctx, cookies, limit)
+	if err != nil {
+		if len(media) == 0 {
+			return cookies, nil, warnings, err
+		}
+		warnings = append(warnings, fmt.Sprintf("liked posts warning: %s", err.Error()))
+	}
+
+	if err := archiveMedia(ctx, archivePath, media); err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	items := instagram.TagItems(instagram.KindMedia, media, includeVideos)
+	items = limit.ApplyItems(items)
+	return cookies, items, warnings, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func (cmd *InstagramPostCmd) Run() error {
+	shortcode := instagram.ParseShortcode(cmd.Post)
+	if shortcode == "" {
+		return fmt.Errorf("shortcode or post URL required")
+	}
+
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	media, err := instagram.FetchPost(ctx, shortcode, cookies)
+	if err != nil {
+		return err
+	}
+	if err := archiveMedia(ctx, cmd.Archive, media); err != nil {
+		return err
+	}
+
+	items := instagram.TagItems(instagram.KindMedia, media, cmd.IncludeVideos)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no images to render")
+		return nil
+	}
+
+	return writeItems(format, items, items[0].Username, cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Group:     cmd.Group,
+		Template:  tmpl,
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"text/template"
 
 	"github.com/steipete/metcli/internal/inline"
 	"github.com/steipete/metcli/internal/instagram"
@@ -25,6 +26,28 @@ type gridOptions struct {
 	ThumbPx   int
 	PaddingPx int
 	PageSize  int
+	// Load reads the image bytes of an item; nil fetches item.URL over HTTP
+	// with the Instagram cookies.
+	Load imageLoader
+	// Group is "posts" to nest JSON output by post; "" or "files" keeps one
+	// entry per file.
+	Group string
+	// Columns selects the csv/tsv columns; nil prints all of them.
+	Columns []tableColumn
+	// Template is executed per item for --format template.
+	Template *template.Template
+}
+
+type imageLoader func(ctx context.Context, item instagram.Item) ([]byte, error)
+
+// httpImageLoader fetches images from the Instagram CDN, sending username as
+// the Referer.
+func httpImageLoader(username string, cookies instagram.CookieBundle) imageLoader {
+	client := instagram.ImageClient()
+	return func(ctx context.Context, item instagram.Item) ([]byte, error) {
+		data, _, _, err := instagram.DownloadImage(ctx, client, item.URL, username, cookies)
+		return data, err
+	}
 }
 
 func renderGrid(items []instagram.Item, username string, cookies instagram.CookieBundle, opts gridOptions) {
@@ -65,7 +88,10 @@ func renderGrid(items []instagram.Item, username string, cookies instagram.Cooki
 	writer := bufio.NewWriter(os.Stdout)
 	defer writer.Flush()
 
-	client := instagram.ImageClient()
+	load := opts.Load
+	if load == nil {
+		load = httpImageLoader(username, cookies)
+	}
 	nextID := uint32(1)
 	for start := 0; start < len(items); start += pageSize {
 		end := start + pageSize
@@ -75,7 +101,7 @@ func renderGrid(items []instagram.Item, username string, cookies instagram.Cooki
 		pageItems := items[start:end]
 		images := make([]image.Image, 0, len(pageItems))
 		for _, item := range pageItems {
-			data, _, _, err := instagram.DownloadImage(context.Background(), client, item.URL, username, cookies)
+			data, err := load(context.Background(), item)
 			if err != nil {
 				_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
 				continue
@@ -127,7 +153,7 @@ func renderGrid(items []instagram.Item, username string, cookies instagram.Cooki
 	}
 }
 
-func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context) error {
+func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context, limit instagram.Limit) error {
 	names := parseNames(cmd.Names)
 	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, names)
 	if err != nil {
@@ -135,6 +161,14 @@ func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context) error {
 	}
 	printWarnings("[metcli]", warnings)
 
+	store, err := openArchive(ctx, cmd.Archive)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
 	protocol := inline.Detect()
 	cols := cmd.ThumbCols
 	if cols <= 0 {
@@ -148,10 +182,15 @@ func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context) error {
 	client := instagram.ImageClient()
 	nextID := uint32(1)
 	rendered := 0
-	_, err = instagram.StreamHomeFeed(ctx, cookies, cmd.Max, cmd.PageSize, cmd.IncludeVideos, func(item instagram.MediaItem) error {
+	_, err = instagram.StreamHomeFeed(ctx, cookies, limit, cmd.PageSize, func(item instagram.MediaItem) error {
 		if item.URL == "" {
 			return nil
 		}
+		if store != nil {
+			if err := store.UpsertMedia(ctx, []instagram.MediaItem{item}); err != nil {
+				return fmt.Errorf("archive media: %w", err)
+			}
+		}
 
 		if protocol == inline.ProtocolNone {
 			if cmd.Text {
@@ -173,49 +212,11 @@ func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context) error {
 			_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
 			return nil
 		}
-		if protocol == inline.ProtocolKitty {
-			data, err = instagram.EnsurePNG(data)
-			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
-				return nil
-			}
-		}
-		if width <= 0 || height <= 0 {
-			cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
-			if err == nil {
-				width = cfg.Width
-				height = cfg.Height
-			}
-		}
-		if width <= 0 || height <= 0 {
-			width = 1
-			height = 1
-		}
-
-		rows := estimateRows(cols, width, height, cellAspect)
-		if rows < 1 {
-			rows = 1
-		}
-
-		switch protocol {
-		case inline.ProtocolIterm:
-			inline.SendItermInline(writer, inline.ItermFile{
-				Name:        inlineName(item.Shortcode),
-				Data:        data,
-				WidthCells:  cols,
-				HeightCells: rows,
-				Stretch:     true,
-			})
-		case inline.ProtocolKitty:
-			inline.SendKittyPNG(writer, nextID, data, cols, rows)
-			nextID++
-		default:
-			_, _ = fmt.Fprintln(os.Stdout, item.URL)
+		if err := sendInlineImage(writer, protocol, nextID, inlineName(item.Shortcode), data, width, height, cols, cellAspect); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
 			return nil
 		}
-
-		advanceCursor(writer, rows)
-		_, _ = fmt.Fprintln(writer)
+		nextID++
 		_ = writer.Flush()
 		rendered++
 		return nil
@@ -232,6 +233,53 @@ func (cmd *InstagramHomeCmd) runInlineStream(ctx context.Context) error {
 	return nil
 }
 
+// sendInlineImage draws one image cols cells wide and moves the cursor below
+// it. Kitty needs PNG, so other formats are converted first.
+func sendInlineImage(writer *bufio.Writer, protocol inline.Protocol, id uint32, name string, data []byte, width, height, cols int, cellAspect float64) error {
+	if protocol == inline.ProtocolKitty {
+		var err error
+		data, err = instagram.EnsurePNG(data)
+		if err != nil {
+			return err
+		}
+	}
+	if width <= 0 || height <= 0 {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err == nil {
+			width = cfg.Width
+			height = cfg.Height
+		}
+	}
+	if width <= 0 || height <= 0 {
+		width = 1
+		height = 1
+	}
+
+	rows := estimateRows(cols, width, height, cellAspect)
+	if rows < 1 {
+		rows = 1
+	}
+
+	switch protocol {
+	case inline.ProtocolIterm:
+		inline.SendItermInline(writer, inline.ItermFile{
+			Name:        name,
+			Data:        data,
+			WidthCells:  cols,
+			HeightCells: rows,
+			Stretch:     true,
+		})
+	case inline.ProtocolKitty:
+		inline.SendKittyPNG(writer, id, data, cols, rows)
+	default:
+		return fmt.Errorf("inline images not supported by this terminal")
+	}
+
+	advanceCursor(writer, rows)
+	_, _ = fmt.Fprintln(writer)
+	return nil
+}
+
 func renderItemText(writer *bufio.Writer, item instagram.MediaItem) {
 	if writer == nil {
 		return
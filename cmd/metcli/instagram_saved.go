@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputCollection struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	MediaCount int    `json:"media_count"`
+}
+
+func (cmd *InstagramSavedCmd) Run() error {
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if cmd.Collections {
+		return cmd.listCollections(ctx, format)
+	}
+
+	cookies, items, warnings, err := loadSavedItems(
+		ctx,
+		cmd.Collection,
+		cmd.Profile,
+		cmd.Names,
+		limit,
+		cmd.IncludeVideos,
+		cmd.Archive,
+	)
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no saved posts")
+		return nil
+	}
+
+	return writeItems(format, items, "", cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Group:     cmd.Group,
+		Template:  tmpl,
+	})
+}
+
+func (cmd *InstagramSavedCmd) listCollections(ctx context.Context, format string) error {
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	collections, err := instagram.FetchCollections(ctx, cookies)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" || format == "jsonl" {
+		payload := make([]outputCollection, 0, len(collections))
+		for _, collection := range collections {
+			payload = append(payload, outputCollection{
+				ID:         collection.ID,
+				Name:       collection.Name,
+				MediaCount: collection.MediaCount,
+			})
+		}
+		return writeRecords(format, payload)
+	}
+	for _, collection := range collections {
+		_, _ = fmt.Fprintf(os.Stdout, "%s\t%d\t%s\n", collection.ID, collection.MediaCount, collection.Name)
+	}
+	return nil
+}
+
+// loadSavedItems fetches the saved posts of the cookie owner, or only those in
+// the named collection.
+func loadSavedItems(
+	ctx context.Context,
+	collectionName string,
+	profilePath string,
+	namesRaw string,
+	limit instagram.Limit,
+	includeVideos bool,
+	archivePath string,
+) (instagram.CookieBundle, []instagram.Item, []string, error) {
+	names := parseNames(namesRaw)
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	var media []instagram.MediaItem
+	if collectionName == "" {
+		media, err = instagram.FetchSavedMedia(ctx, cookies, limit)
+	} else {
+		collections, listErr := instagram.FetchCollections(ctx, cookies)
+		if listErr != nil {
+			return cookies, nil, warnings, listErr
+		}
+		collection, ok := instagram.FindCollection(collections, collectionName)
+		if !ok {
+			return cookies, nil, warnings, fmt.Errorf("no saved collection named %q (see --collections)", collectionName)
+		}
+		media, err = instagram.FetchCollectionMedia(ctx, collection.ID, cookies, limit)
+	}
+	if err != nil {
+		if len(media) == 0 {
+			return cookies, nil, warnings, err
+		}
+		warnings = append(warnings, fmt.Sprintf("saved posts warning: %s", err.Error()))
+	}
+
+	if err := archiveMedia(ctx, archivePath, media); err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	items := instagram.TagItems(instagram.KindMedia, media, includeVideos)
+	items = limit.ApplyItems(items)
+	return cookies, items, warnings, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func (cmd *InstagramStoriesCmd) Run() error {
+	username := instagram.ParseUsername(cmd.User)
+	if username == "" && !cmd.Tray {
+		return fmt.Errorf("username or profile URL required (or --tray)")
+	}
+	if username != "" && cmd.Tray {
+		return fmt.Errorf("use either a username or --tray, not both")
+	}
+
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cookies, items, warnings, err := loadStoryItems(
+		ctx,
+		username,
+		cmd.Profile,
+		cmd.Names,
+		cmd.Max,
+		cmd.IncludeVideos,
+	)
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no active stories")
+		return nil
+	}
+
+	return writeItems(format, items, username, cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Template:  tmpl,
+	})
+}
+
+// loadStoryItems fetches the stories of username, or the whole story tray
+// when username is empty.
+func loadStoryItems(
+	ctx context.Context,
+	username string,
+	profilePath string,
+	namesRaw string,
+	max int,
+	includeVideos bool,
+) (instagram.CookieBundle, []instagram.Item, []string, error) {
+	names := parseNames(namesRaw)
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
+	if err != nil {
+		return cookies, nil, warnings, err
+	}
+
+	var media []instagram.MediaItem
+	if username == "" {
+		media, err = instagram.FetchStoryTray(ctx, cookies, max)
+	} else {
+		profile, profileErr := instagram.FetchProfile(ctx, username, cookies)
+		if profileErr != nil {
+			return cookies, nil, warnings, profileErr
+		}
+		media, err = instagram.FetchUserStories(ctx, username, profile.UserID, cookies)
+	}
+	if err != nil {
+		if len(media) == 0 {
+			return cookies, nil, warnings, err
+		}
+		warnings = append(warnings, fmt.Sprintf("stories warning: %s", err.Error()))
+	}
+
+	items := instagram.TagItems(instagram.KindStory, media, includeVideos)
+	if max > 0 && len(items) > max {
+		items = items[:max]
+	}
+	return cookies, items, warnings, nil
+}
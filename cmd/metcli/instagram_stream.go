@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// streamProfileJSONL prints the api-source items of username as JSON Lines
+// page by page, rather than after the whole feed has been crawled.
+func streamProfileJSONL(
+	ctx context.Context,
+	username string,
+	profilePath string,
+	namesRaw string,
+	pageSize int,
+	limit instagram.Limit,
+	avatar bool,
+	includeVideos bool,
+	archivePath string,
+	group string,
+) error {
+	byPost, err := groupByPost(group)
+	if err != nil {
+		return err
+	}
+	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, parseNames(namesRaw))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	profile, err := instagram.FetchProfile(ctx, username, cookies)
+	if err != nil {
+		return err
+	}
+	store, err := openArchive(ctx, archivePath)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer store.Close()
+		head := profile
+		head.Media = nil
+		if err := store.UpsertProfile(ctx, head); err != nil {
+			return fmt.Errorf("archive profile: %w", err)
+		}
+	}
+
+	w := newJSONLWriter(os.Stdout, byPost)
+	written := 0
+	if avatar {
+		head := profile
+		head.Media = nil
+		for _, item := range instagram.BuildItems(head, true, includeVideos) {
+			if err := w.Write(item); err != nil {
+				return err
+			}
+			written++
+		}
+	}
+
+	err = instagram.StreamUserMedia(ctx, username, profile, cookies, limit, pageSize, func(item instagram.MediaItem) error {
+		if store != nil {
+			if err := store.UpsertMedia(ctx, []instagram.MediaItem{item}); err != nil {
+				return fmt.Errorf("archive media: %w", err)
+			}
+		}
+		written++
+		return w.Write(instagram.Item{Kind: instagram.KindMedia, MediaItem: item})
+	})
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+	if err != nil {
+		if written == 0 {
+			return err
+		}
+		printWarnings("[metcli]", []string{fmt.Sprintf("media fetch warning: %s", err.Error())})
+	}
+	if written == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no media found")
+	}
+	return nil
+}
+
+// runJSONLStream prints the home timeline as JSON Lines while it is paged.
+func (cmd *InstagramHomeCmd) runJSONLStream(ctx context.Context, limit instagram.Limit) error {
+	byPost, err := groupByPost(cmd.Group)
+	if err != nil {
+		return err
+	}
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	store, err := openArchive(ctx, cmd.Archive)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
+	w := newJSONLWriter(os.Stdout, byPost)
+	count, err := instagram.StreamHomeFeed(ctx, cookies, limit, cmd.PageSize, func(item instagram.MediaItem) error {
+		if store != nil {
+			if err := store.UpsertMedia(ctx, []instagram.MediaItem{item}); err != nil {
+				return fmt.Errorf("archive media: %w", err)
+			}
+		}
+		return w.Write(instagram.Item{Kind: instagram.KindMedia, MediaItem: item})
+	})
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+	if err != nil {
+		if count == 0 {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "[metcli] home feed warning: %s\n", err.Error())
+	}
+	if count == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no media found")
+	}
+	return nil
+}
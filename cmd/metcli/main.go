@@ -2,103 +2,443 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/alecthomas/kong"
-	"github.com/steipete/metcli/internal/inline"
 	"github.com/steipete/metcli/internal/instagram"
 	"golang.org/x/term"
 )
 
 type CLI struct {
 	Instagram InstagramCmd `cmd:"" help:"Instagram helpers"`
+	Facebook  FacebookCmd  `cmd:"" help:"Facebook helpers"`
+	Import    ImportCmd    `cmd:"" help:"Read Meta data exports offline"`
+	Threads   ThreadsCmd   `cmd:"" help:"Threads helpers (uses the Instagram session)"`
+	WhatsApp  WhatsAppCmd  `cmd:"" name:"whatsapp" help:"WhatsApp helpers"`
+}
+
+type ThreadsCmd struct {
+	Posts ThreadsPostsCmd `cmd:"" help:"Show the threads or replies an account posted"`
+	Post  ThreadsPostCmd  `cmd:"" help:"Show a thread with its replies"`
+}
+
+type WhatsAppCmd struct {
+	Import WhatsAppImportCmd `cmd:"" help:"Read a WhatsApp chat export"`
+}
+
+type FacebookCmd struct {
+	Import FacebookImportCmd `cmd:"" help:"Read a Facebook Download Your Information export"`
+}
+
+type ImportCmd struct {
+	InstagramDYI ImportInstagramDYICmd `cmd:"" name:"instagram-dyi" help:"Read an Instagram Download Your Information export (JSON or HTML format)"`
 }
 
 type InstagramCmd struct {
-	Profile InstagramProfileCmd `cmd:"" help:"Show profile images"`
-	Feed    InstagramFeedCmd    `cmd:"" help:"Show feed images"`
-	Home    InstagramHomeCmd    `cmd:"" help:"Show home timeline images"`
-	URLs    InstagramURLsCmd    `cmd:"" name:"urls" help:"List profile image URLs"`
+	Profile    InstagramProfileCmd    `cmd:"" help:"Show profile images"`
+	Feed       InstagramFeedCmd       `cmd:"" help:"Show feed images"`
+	Home       InstagramHomeCmd       `cmd:"" help:"Show home timeline images"`
+	URLs       InstagramURLsCmd       `cmd:"" name:"urls" help:"List profile image URLs"`
+	Download   InstagramDownloadCmd   `cmd:"" help:"Download full-resolution media with JSON sidecars"`
+	Stories    InstagramStoriesCmd    `cmd:"" help:"Show active stories of a user or the story tray"`
+	Highlights InstagramHighlightsCmd `cmd:"" help:"List story highlights of a profile"`
+	Saved      InstagramSavedCmd      `cmd:"" help:"Show saved posts and collections of the logged-in account"`
+	Liked      InstagramLikedCmd      `cmd:"" help:"Show posts liked by the logged-in account"`
+	Comments   InstagramCommentsCmd   `cmd:"" help:"Export the comment thread of a post"`
+	Followers  InstagramFollowersCmd  `cmd:"" help:"Export the followers of a profile"`
+	Following  InstagramFollowingCmd  `cmd:"" help:"Export the accounts a profile follows"`
+	Post       InstagramPostCmd       `cmd:"" help:"Show a single post by shortcode or URL"`
+	DMs        InstagramDMsCmd        `cmd:"" name:"dms" help:"Export direct message threads"`
 }
 
 type InstagramProfileCmd struct {
 	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
-	Format        string `help:"auto|inline|url|json" default:"auto"`
+	Format        string `help:"auto|inline|url|json|jsonl|csv|tsv|template" default:"auto"`
 	Inline        bool   `help:"shorthand for --format inline"`
 	URL           bool   `help:"shorthand for --format url"`
 	JSON          bool   `help:"shorthand for --format json"`
+	Columns       string `help:"comma-separated csv/tsv columns (default: all)"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
 	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
 	Avatar        bool   `help:"include profile picture" default:"true" negatable:""`
-	IncludeVideos bool   `help:"include video thumbnails" default:"true" negatable:""`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
 	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
 	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
 	GridCols      int    `help:"grid columns" default:"4"`
 	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
 	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
 	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
 	PageSize      int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+	RangeFlags    `embed:""`
+	FilterFlags   `embed:""`
 }
 
 type InstagramFeedCmd struct {
 	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
-	Format        string `help:"url|inline|json" default:"url"`
+	Format        string `help:"url|inline|json|jsonl|csv|tsv|template (jsonl streams with --source api; other sources print once fully fetched)" default:"url"`
 	Inline        bool   `help:"shorthand for --format inline"`
 	URL           bool   `help:"shorthand for --format url"`
 	JSON          bool   `help:"shorthand for --format json"`
+	Columns       string `help:"comma-separated csv/tsv columns (default: all)"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
 	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
 	Avatar        bool   `help:"include profile picture" default:"true" negatable:""`
-	IncludeVideos bool   `help:"include video thumbnails" default:"true" negatable:""`
-	Source        string `help:"main|api" default:"api"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Source        string `help:"main|api|reels|all|tagged (all = grid plus reels-only posts)" default:"api"`
 	PageSize      int    `help:"items per API page (1-50)" default:"50"`
 	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
 	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
 	GridCols      int    `help:"grid columns" default:"4"`
 	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
 	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
 	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
 	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+	RangeFlags    `embed:""`
+	FilterFlags   `embed:""`
 }
 
 type InstagramURLsCmd struct {
 	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
+	Format        string `help:"url|csv|tsv" default:"url"`
+	Columns       string `help:"comma-separated csv/tsv columns (default: all)"`
 	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
 	Avatar        bool   `help:"include profile picture" default:"true" negatable:""`
-	IncludeVideos bool   `help:"include video thumbnails" default:"true" negatable:""`
-	Source        string `help:"main|api" default:"api"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Source        string `help:"main|api|reels|all|tagged (all = grid plus reels-only posts)" default:"api"`
 	PageSize      int    `help:"items per API page (1-50)" default:"50"`
 	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
 	Names         string `help:"comma-separated cookie names"`
+
+	RangeFlags  `embed:""`
+	FilterFlags `embed:""`
 }
 
 type InstagramHomeCmd struct {
-	Format        string `help:"url|inline|json" default:"inline"`
+	Format        string `help:"url|inline|json|jsonl|csv|tsv|template" default:"inline"`
 	Inline        bool   `help:"shorthand for --format inline"`
 	URL           bool   `help:"shorthand for --format url"`
 	JSON          bool   `help:"shorthand for --format json"`
+	Columns       string `help:"comma-separated csv/tsv columns (default: all)"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
 	Max           int    `help:"max items (0 = all)" default:"0"`
-	IncludeVideos bool   `help:"include video thumbnails" default:"true" negatable:""`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
 	Text          bool   `help:"show username + caption" default:"true" negatable:""`
 	PageSize      int    `help:"items per API page (1-50)" default:"50"`
 	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
 	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
 	GridCols      int    `help:"grid columns" default:"4"`
 	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
 	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
 	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
 	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+	RangeFlags    `embed:""`
+	FilterFlags   `embed:""`
+}
+
+type InstagramDownloadCmd struct {
+	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL (omit for --source home, or stories to use the story tray)"`
+	Out           string `help:"target directory" default:"." type:"path"`
+	Source        string `help:"main|api|reels|all|tagged|home|stories|highlights" default:"api"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
+	Avatar        bool   `help:"include profile picture" default:"true" negatable:""`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Sidecar       bool   `help:"write a JSON sidecar next to each file" default:"true" negatable:""`
+	Force         bool   `help:"re-download files that already exist"`
+	Sync          bool   `help:"only fetch posts newer than the last run and resume interrupted crawls (ignores --max)"`
+	PageSize      int    `help:"items per API page (1-50)" default:"50"`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
+
+	RangeFlags  `embed:""`
+	FilterFlags `embed:""`
+}
+
+type InstagramStoriesCmd struct {
+	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
+	Tray          bool   `help:"stories of all followed accounts from the story tray"`
+	Format        string `help:"auto|inline|url|json|jsonl|template" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+}
+
+type InstagramHighlightsCmd struct {
+	User          string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
+	Expand        bool   `help:"expand highlights into their items"`
+	Title         string `help:"only highlights whose title contains this (case-insensitive)"`
+	Format        string `help:"auto|inline|url|json|jsonl|template" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+}
+
+type InstagramSavedCmd struct {
+	Collection    string `help:"only posts saved to this collection (name or id)"`
+	Collections   bool   `help:"list collection names and post counts instead of posts"`
+	Format        string `help:"auto|inline|url|json|jsonl|template" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+	RangeFlags    `embed:""`
+	FilterFlags   `embed:""`
+}
+
+type InstagramLikedCmd struct {
+	Format        string `help:"auto|inline|url|json|jsonl|template" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	MaxPosts      int    `help:"max posts, counting a carousel once (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+	RangeFlags    `embed:""`
+	FilterFlags   `embed:""`
+}
+
+type InstagramCommentsCmd struct {
+	Post    string `arg:"" optional:"" name:"post" help:"Shortcode or post URL"`
+	Format  string `help:"text|json|jsonl" default:"text"`
+	JSON    bool   `help:"shorthand for --format json"`
+	Max     int    `help:"max top-level comments (0 = all)" default:"0"`
+	Replies bool   `help:"fetch every reply, not just the previews" default:"true" negatable:""`
+	Profile string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names   string `help:"comma-separated cookie names"`
+}
+
+type InstagramFollowersCmd struct {
+	User     string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
+	Format   string `help:"json|jsonl|csv" default:"json"`
+	Max      int    `help:"max accounts (0 = all)" default:"0"`
+	PageSize int    `help:"accounts per API page (1-200)" default:"50"`
+	Profile  string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names    string `help:"comma-separated cookie names"`
+}
+
+type InstagramFollowingCmd struct {
+	User     string `arg:"" optional:"" name:"user" help:"Username or profile URL"`
+	Format   string `help:"json|jsonl|csv" default:"json"`
+	Max      int    `help:"max accounts (0 = all)" default:"0"`
+	PageSize int    `help:"accounts per API page (1-200)" default:"50"`
+	Profile  string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names    string `help:"comma-separated cookie names"`
+}
+
+type InstagramPostCmd struct {
+	Post          string `arg:"" optional:"" name:"post" help:"Shortcode or post/reel URL"`
+	Format        string `help:"auto|inline|url|json|jsonl|template" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Group         string `help:"files|posts (posts nests carousel children in JSON output)" default:"files"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	Archive       string `help:"SQLite archive to record fetched profiles and media in" type:"path"`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+}
+
+type InstagramDMsCmd struct {
+	Thread  string `arg:"" optional:"" name:"thread" help:"Thread id (omit to list the inbox)"`
+	Format  string `help:"text|json|jsonl" default:"text"`
+	JSON    bool   `help:"shorthand for --format json"`
+	Max     int    `help:"max threads, or newest messages of a thread (0 = all)" default:"0"`
+	Profile string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names   string `help:"comma-separated cookie names"`
+}
+
+type ImportInstagramDYICmd struct {
+	Path          string `arg:"" optional:"" name:"zip" help:"Export ZIP or the directory it was extracted to" type:"path"`
+	Section       string `help:"posts|stories|reels|media|liked|comments|followers|following|messages|summary" default:"posts"`
+	Thread        string `help:"only the message thread whose id or title contains this"`
+	Format        string `help:"auto|inline|url|json|jsonl|template|text (text for non-media sections)" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos" default:"true" negatable:""`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+}
+
+type FacebookImportCmd struct {
+	Path          string `arg:"" optional:"" name:"zip" help:"Export ZIP or the directory it was extracted to" type:"path"`
+	Section       string `help:"posts|albums|photos|media|comments|reactions|friends|messages|summary" default:"posts"`
+	Album         string `help:"only the album whose name contains this"`
+	Thread        string `help:"only the message thread whose id or title contains this"`
+	Format        string `help:"auto|inline|url|json|jsonl|template|text (text for non-media sections)" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max items (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos" default:"true" negatable:""`
+	GridCols      int    `help:"grid columns" default:"4"`
+	ThumbCols     int    `help:"thumb width in cells (0 = auto)" default:"0"`
+	ThumbPx       int    `help:"thumbnail size in px" default:"256"`
+	PaddingPx     int    `help:"padding between thumbs in px" default:"8"`
+	PageGridSize  int    `help:"images per grid page (0 = auto)" default:"0"`
+
+	TemplateFlags `embed:""`
+}
+
+type ThreadsPostsCmd struct {
+	User          string `arg:"" optional:"" name:"user" help:"Username, @handle or threads.net profile URL"`
+	Replies       bool   `help:"show the replies tab instead of threads"`
+	Format        string `help:"auto|text|inline|url|json|jsonl (auto = inline in capable terminals, text otherwise)" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max posts (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	ThumbCols     int    `help:"image width in cells (0 = auto)" default:"0"`
+}
+
+type ThreadsPostCmd struct {
+	Post          string `arg:"" optional:"" name:"post" help:"Shortcode or threads.net post URL"`
+	Format        string `help:"auto|text|inline|url|json|jsonl (auto = inline in capable terminals, text otherwise)" default:"auto"`
+	Inline        bool   `help:"shorthand for --format inline"`
+	URL           bool   `help:"shorthand for --format url"`
+	JSON          bool   `help:"shorthand for --format json"`
+	Max           int    `help:"max replies (0 = all)" default:"0"`
+	IncludeVideos bool   `help:"include videos (thumbnails inline, MP4 otherwise)" default:"true" negatable:""`
+	Profile       string `help:"Chrome profile name/dir or Cookies DB path"`
+	Names         string `help:"comma-separated cookie names"`
+	ThumbCols     int    `help:"image width in cells (0 = auto)" default:"0"`
+}
+
+type WhatsAppImportCmd struct {
+	Path      string `arg:"" optional:"" name:"path" help:"Chat export ZIP or its _chat.txt" type:"path"`
+	Format    string `help:"auto|text|inline|jsonl|json" default:"auto"`
+	JSON      bool   `help:"shorthand for --format json"`
+	Sender    string `help:"only messages whose sender contains this"`
+	TZ        string `name:"tz" help:"time zone the chat was exported in (default local)"`
+	Max       int    `help:"max messages (0 = all)" default:"0"`
+	ThumbCols int    `help:"image width in cells (0 = auto)" default:"0"`
 }
 
 type outputItem struct {
-	URL       string `json:"url"`
-	Kind      string `json:"kind"`
-	IsVideo   bool   `json:"is_video"`
-	Shortcode string `json:"shortcode,omitempty"`
-	TakenAt   int64  `json:"taken_at,omitempty"`
-	Username  string `json:"username,omitempty"`
-	Caption   string `json:"caption,omitempty"`
+	URL           string  `json:"url"`
+	Kind          string  `json:"kind"`
+	IsVideo       bool    `json:"is_video"`
+	VideoURL      string  `json:"video_url,omitempty"`
+	VideoDuration float64 `json:"video_duration,omitempty"`
+	Width         int     `json:"width,omitempty"`
+	Height        int     `json:"height,omitempty"`
+	Shortcode     string  `json:"shortcode,omitempty"`
+	TakenAt       int64   `json:"taken_at,omitempty"`
+	Username      string  `json:"username,omitempty"`
+	Caption       string  `json:"caption,omitempty"`
+	ExpiringAt    int64   `json:"expiring_at,omitempty"`
+	Highlight     string  `json:"highlight,omitempty"`
+	PlayCount     int64   `json:"play_count,omitempty"`
+	ViewCount     int64   `json:"view_count,omitempty"`
+	AudioTitle    string  `json:"audio_title,omitempty"`
+	AudioArtist   string  `json:"audio_artist,omitempty"`
+	TaggedAccount string  `json:"tagged_account,omitempty"`
+
+	MediaID              string          `json:"media_id,omitempty"`
+	ProductType          string          `json:"product_type,omitempty"`
+	Pinned               bool            `json:"pinned,omitempty"`
+	LikeCount            int64           `json:"like_count,omitempty"`
+	CommentCount         int64           `json:"comment_count,omitempty"`
+	Location             *outputLocation `json:"location,omitempty"`
+	AccessibilityCaption string          `json:"accessibility_caption,omitempty"`
+	TaggedUsers          []string        `json:"tagged_users,omitempty"`
+	Coauthors            []string        `json:"coauthors,omitempty"`
+	CarouselIndex        int             `json:"carousel_index,omitempty"`
+	Hashtags             []string        `json:"hashtags,omitempty"`
+	Mentions             []string        `json:"mentions,omitempty"`
+}
+
+type outputPost struct {
+	Shortcode string       `json:"shortcode,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	TakenAt   int64        `json:"taken_at,omitempty"`
+	Caption   string       `json:"caption,omitempty"`
+	Children  []outputItem `json:"children"`
+}
+
+type outputLocation struct {
+	ID   string  `json:"id,omitempty"`
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat,omitempty"`
+	Lng  float64 `json:"lng,omitempty"`
 }
 
 func main() {
@@ -133,6 +473,118 @@ func main() {
 		if err := cli.Instagram.URLs.Run(); err != nil {
 			fail(err)
 		}
+	case "instagram download <user>":
+		if err := cli.Instagram.Download.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram download":
+		if err := cli.Instagram.Download.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram stories <user>":
+		if err := cli.Instagram.Stories.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram stories":
+		if err := cli.Instagram.Stories.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram highlights <user>":
+		if err := cli.Instagram.Highlights.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram highlights":
+		if err := cli.Instagram.Highlights.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram saved":
+		if err := cli.Instagram.Saved.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram liked":
+		if err := cli.Instagram.Liked.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram comments <post>":
+		if err := cli.Instagram.Comments.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram comments":
+		if err := cli.Instagram.Comments.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram followers <user>":
+		if err := cli.Instagram.Followers.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram followers":
+		if err := cli.Instagram.Followers.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram following <user>":
+		if err := cli.Instagram.Following.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram following":
+		if err := cli.Instagram.Following.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram post <post>":
+		if err := cli.Instagram.Post.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram post":
+		if err := cli.Instagram.Post.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram dms <thread>":
+		if err := cli.Instagram.DMs.Run(); err != nil {
+			fail(err)
+		}
+	case "instagram dms":
+		if err := cli.Instagram.DMs.Run(); err != nil {
+			fail(err)
+		}
+	case "import instagram-dyi <zip>":
+		if err := cli.Import.InstagramDYI.Run(); err != nil {
+			fail(err)
+		}
+	case "import instagram-dyi":
+		if err := cli.Import.InstagramDYI.Run(); err != nil {
+			fail(err)
+		}
+	case "facebook import <zip>":
+		if err := cli.Facebook.Import.Run(); err != nil {
+			fail(err)
+		}
+	case "facebook import":
+		if err := cli.Facebook.Import.Run(); err != nil {
+			fail(err)
+		}
+	case "threads posts <user>":
+		if err := cli.Threads.Posts.Run(); err != nil {
+			fail(err)
+		}
+	case "threads posts":
+		if err := cli.Threads.Posts.Run(); err != nil {
+			fail(err)
+		}
+	case "threads post <post>":
+		if err := cli.Threads.Post.Run(); err != nil {
+			fail(err)
+		}
+	case "threads post":
+		if err := cli.Threads.Post.Run(); err != nil {
+			fail(err)
+		}
+	case "whatsapp import <path>":
+		if err := cli.WhatsApp.Import.Run(); err != nil {
+			fail(err)
+		}
+	case "whatsapp import":
+		if err := cli.WhatsApp.Import.Run(); err != nil {
+			fail(err)
+		}
 	default:
 		fail(fmt.Errorf("unknown command: %s", cmd))
 	}
@@ -144,28 +596,38 @@ func (cmd *InstagramProfileCmd) Run() error {
 		return fmt.Errorf("username or profile URL required")
 	}
 
-	format := strings.ToLower(strings.TrimSpace(cmd.Format))
-	if cmd.Inline {
-		format = "inline"
-	}
-	if cmd.URL {
-		format = "url"
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
 	}
-	if cmd.JSON {
-		format = "json"
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
 	}
-	if format == "auto" {
-		if isTerminal(os.Stdout) && inline.Detect() != inline.ProtocolNone {
-			format = "inline"
-		} else {
-			format = "url"
-		}
+	columns, err := selectColumns(cmd.Columns)
+	if err != nil {
+		return err
 	}
-	if format != "inline" && format != "url" && format != "json" {
-		return fmt.Errorf("unsupported format: %s", format)
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
 	}
 
 	ctx := context.Background()
+	if format == "jsonl" {
+		return streamProfileJSONL(
+			ctx,
+			username,
+			cmd.Profile,
+			cmd.Names,
+			50,
+			limit,
+			cmd.Avatar,
+			cmd.IncludeVideos,
+			cmd.Archive,
+			cmd.Group,
+		)
+	}
 	cookies, items, warnings, err := loadInstagramItems(
 		ctx,
 		username,
@@ -173,9 +635,10 @@ func (cmd *InstagramProfileCmd) Run() error {
 		cmd.Names,
 		"api",
 		50,
-		cmd.Max,
+		limit,
 		cmd.Avatar,
 		cmd.IncludeVideos,
+		cmd.Archive,
 	)
 	if err != nil {
 		return err
@@ -186,42 +649,16 @@ func (cmd *InstagramProfileCmd) Run() error {
 		return nil
 	}
 
-	switch format {
-	case "json":
-		payload := make([]outputItem, 0, len(items))
-		for _, item := range items {
-			payload = append(payload, outputItem{
-				URL:       item.URL,
-				Kind:      item.Kind,
-				IsVideo:   item.IsVideo,
-				Shortcode: item.Shortcode,
-				TakenAt:   item.TakenAt,
-				Username:  item.Username,
-				Caption:   item.Caption,
-			})
-		}
-		encoded, err := json.MarshalIndent(payload, "", "  ")
-		if err != nil {
-			return err
-		}
-		_, _ = fmt.Fprintln(os.Stdout, string(encoded))
-	case "url":
-		for _, item := range items {
-			_, _ = fmt.Fprintln(os.Stdout, item.URL)
-		}
-	case "inline":
-		renderGrid(items, username, cookies, gridOptions{
-			GridCols:  cmd.GridCols,
-			ThumbCols: cmd.ThumbCols,
-			ThumbPx:   cmd.ThumbPx,
-			PaddingPx: cmd.PaddingPx,
-			PageSize:  cmd.PageSize,
-		})
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-
-	return nil
+	return writeItems(format, items, username, cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageSize,
+		Group:     cmd.Group,
+		Columns:   columns,
+		Template:  tmpl,
+	})
 }
 
 func (cmd *InstagramFeedCmd) Run() error {
@@ -230,21 +667,38 @@ func (cmd *InstagramFeedCmd) Run() error {
 		return fmt.Errorf("username or profile URL required")
 	}
 
-	format := strings.ToLower(strings.TrimSpace(cmd.Format))
-	if cmd.Inline {
-		format = "inline"
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
 	}
-	if cmd.URL {
-		format = "url"
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
 	}
-	if cmd.JSON {
-		format = "json"
+	columns, err := selectColumns(cmd.Columns)
+	if err != nil {
+		return err
 	}
-	if format != "inline" && format != "url" && format != "json" {
-		return fmt.Errorf("unsupported format: %s", format)
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
 	}
 
 	ctx := context.Background()
+	if format == "jsonl" && strings.ToLower(strings.TrimSpace(cmd.Source)) == "api" {
+		return streamProfileJSONL(
+			ctx,
+			username,
+			cmd.Profile,
+			cmd.Names,
+			cmd.PageSize,
+			limit,
+			cmd.Avatar,
+			cmd.IncludeVideos,
+			cmd.Archive,
+			cmd.Group,
+		)
+	}
 	cookies, items, warnings, err := loadInstagramItems(
 		ctx,
 		username,
@@ -252,9 +706,10 @@ func (cmd *InstagramFeedCmd) Run() error {
 		cmd.Names,
 		cmd.Source,
 		cmd.PageSize,
-		cmd.Max,
+		limit,
 		cmd.Avatar,
 		cmd.IncludeVideos,
+		cmd.Archive,
 	)
 	if err != nil {
 		return err
@@ -265,42 +720,16 @@ func (cmd *InstagramFeedCmd) Run() error {
 		return nil
 	}
 
-	switch format {
-	case "json":
-		payload := make([]outputItem, 0, len(items))
-		for _, item := range items {
-			payload = append(payload, outputItem{
-				URL:       item.URL,
-				Kind:      item.Kind,
-				IsVideo:   item.IsVideo,
-				Shortcode: item.Shortcode,
-				TakenAt:   item.TakenAt,
-				Username:  item.Username,
-				Caption:   item.Caption,
-			})
-		}
-		encoded, err := json.MarshalIndent(payload, "", "  ")
-		if err != nil {
-			return err
-		}
-		_, _ = fmt.Fprintln(os.Stdout, string(encoded))
-	case "url":
-		for _, item := range items {
-			_, _ = fmt.Fprintln(os.Stdout, item.URL)
-		}
-	case "inline":
-		renderGrid(items, username, cookies, gridOptions{
-			GridCols:  cmd.GridCols,
-			ThumbCols: cmd.ThumbCols,
-			ThumbPx:   cmd.ThumbPx,
-			PaddingPx: cmd.PaddingPx,
-			PageSize:  cmd.PageGridSize,
-		})
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-
-	return nil
+	return writeItems(format, items, username, cookies, gridOptions{
+		GridCols:  cmd.GridCols,
+		ThumbCols: cmd.ThumbCols,
+		ThumbPx:   cmd.ThumbPx,
+		PaddingPx: cmd.PaddingPx,
+		PageSize:  cmd.PageGridSize,
+		Group:     cmd.Group,
+		Columns:   columns,
+		Template:  tmpl,
+	})
 }
 
 func (cmd *InstagramURLsCmd) Run() error {
@@ -308,6 +737,18 @@ func (cmd *InstagramURLsCmd) Run() error {
 	if username == "" {
 		return fmt.Errorf("username or profile URL required")
 	}
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if format != "url" && format != "csv" && format != "tsv" {
+		return fmt.Errorf("unsupported format: %s", cmd.Format)
+	}
+	columns, err := selectColumns(cmd.Columns)
+	if err != nil {
+		return err
+	}
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 	_, items, warnings, err := loadInstagramItems(
@@ -317,46 +758,57 @@ func (cmd *InstagramURLsCmd) Run() error {
 		cmd.Names,
 		cmd.Source,
 		cmd.PageSize,
-		cmd.Max,
+		limit,
 		cmd.Avatar,
 		cmd.IncludeVideos,
+		"",
 	)
 	if err != nil {
 		return err
 	}
 	printWarnings("[metcli]", warnings)
+	if format != "url" {
+		return writeTable(os.Stdout, format, items, columns)
+	}
 	for _, item := range items {
-		_, _ = fmt.Fprintln(os.Stdout, item.URL)
+		_, _ = fmt.Fprintln(os.Stdout, item.DownloadURL())
 	}
 	return nil
 }
 
 func (cmd *InstagramHomeCmd) Run() error {
-	format := strings.ToLower(strings.TrimSpace(cmd.Format))
-	if cmd.Inline {
-		format = "inline"
+	format, err := resolveFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
 	}
-	if cmd.URL {
-		format = "url"
+	tmpl, err := cmd.TemplateFlags.parse(format)
+	if err != nil {
+		return err
 	}
-	if cmd.JSON {
-		format = "json"
+	columns, err := selectColumns(cmd.Columns)
+	if err != nil {
+		return err
 	}
-	if format != "inline" && format != "url" && format != "json" {
-		return fmt.Errorf("unsupported format: %s", format)
+	limit, err := mediaLimit(cmd.RangeFlags, cmd.FilterFlags, cmd.Max, cmd.MaxPosts, cmd.IncludeVideos)
+	if err != nil {
+		return err
 	}
 
 	ctx := context.Background()
-	if format == "inline" {
-		return cmd.runInlineStream(ctx)
+	switch format {
+	case "inline":
+		return cmd.runInlineStream(ctx, limit)
+	case "jsonl":
+		return cmd.runJSONLStream(ctx, limit)
 	}
-	_, items, warnings, err := loadHomeItems(
+	cookies, items, warnings, err := loadHomeItems(
 		ctx,
 		cmd.Profile,
 		cmd.Names,
 		cmd.PageSize,
-		cmd.Max,
+		limit,
 		cmd.IncludeVideos,
+		cmd.Archive,
 	)
 	if err != nil {
 		return err
@@ -367,34 +819,7 @@ func (cmd *InstagramHomeCmd) Run() error {
 		return nil
 	}
 
-	switch format {
-	case "json":
-		payload := make([]outputItem, 0, len(items))
-		for _, item := range items {
-			payload = append(payload, outputItem{
-				URL:       item.URL,
-				Kind:      item.Kind,
-				IsVideo:   item.IsVideo,
-				Shortcode: item.Shortcode,
-				TakenAt:   item.TakenAt,
-				Username:  item.Username,
-				Caption:   item.Caption,
-			})
-		}
-		encoded, err := json.MarshalIndent(payload, "", "  ")
-		if err != nil {
-			return err
-		}
-		_, _ = fmt.Fprintln(os.Stdout, string(encoded))
-	case "url":
-		for _, item := range items {
-			_, _ = fmt.Fprintln(os.Stdout, item.URL)
-		}
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-
-	return nil
+	return writeItems(format, items, "", cookies, gridOptions{Group: cmd.Group, Columns: columns, Template: tmpl})
 }
 
 func loadInstagramItems(
@@ -404,9 +829,10 @@ func loadInstagramItems(
 	namesRaw string,
 	source string,
 	pageSize int,
-	max int,
+	limit instagram.Limit,
 	avatar bool,
 	includeVideos bool,
+	archivePath string,
 ) (instagram.CookieBundle, []instagram.Item, []string, error) {
 	names := parseNames(namesRaw)
 	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
@@ -427,7 +853,7 @@ func loadInstagramItems(
 	case "main":
 		// keep profile.Media as-is
 	case "api":
-		media, err := instagram.FetchUserMedia(ctx, username, profile, cookies, max, pageSize)
+		media, err := instagram.FetchUserMedia(ctx, username, profile, cookies, limit, pageSize)
 		if err != nil {
 			if len(media) == 0 {
 				return cookies, nil, warnings, err
@@ -435,14 +861,47 @@ func loadInstagramItems(
 			warnings = append(warnings, fmt.Sprintf("media fetch warning: %s", err.Error()))
 		}
 		profile.Media = media
+	case "reels":
+		media, err := instagram.FetchUserReels(ctx, username, profile.UserID, cookies, limit, pageSize)
+		if err != nil {
+			if len(media) == 0 {
+				return cookies, nil, warnings, err
+			}
+			warnings = append(warnings, fmt.Sprintf("reels fetch warning: %s", err.Error()))
+		}
+		profile.Media = media
+	case "tagged":
+		media, err := instagram.FetchTaggedMedia(ctx, username, profile.UserID, cookies, limit, pageSize)
+		if err != nil {
+			if len(media) == 0 {
+				return cookies, nil, warnings, err
+			}
+			warnings = append(warnings, fmt.Sprintf("tagged fetch warning: %s", err.Error()))
+		}
+		profile.Media = media
+	case "all":
+		media, err := instagram.FetchUserMedia(ctx, username, profile, cookies, limit, pageSize)
+		if err != nil {
+			if len(media) == 0 {
+				return cookies, nil, warnings, err
+			}
+			warnings = append(warnings, fmt.Sprintf("media fetch warning: %s", err.Error()))
+		}
+		reels, err := instagram.FetchUserReels(ctx, username, profile.UserID, cookies, instagram.ReelsLimit(limit, media), pageSize)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("reels fetch warning: %s", err.Error()))
+		}
+		profile.Media = instagram.MergeReels(media, reels)
 	default:
 		return cookies, nil, warnings, fmt.Errorf("unsupported source: %s", source)
 	}
 
-	items := instagram.BuildItems(profile, avatar, includeVideos)
-	if max > 0 && len(items) > max {
-		items = items[:max]
+	if err := archiveProfile(ctx, archivePath, profile); err != nil {
+		return cookies, nil, warnings, err
 	}
+
+	items := instagram.BuildItems(profile, avatar, includeVideos)
+	items = limit.ApplyItems(items)
 	return cookies, items, warnings, nil
 }
 
@@ -451,8 +910,9 @@ func loadHomeItems(
 	profilePath string,
 	namesRaw string,
 	pageSize int,
-	max int,
+	limit instagram.Limit,
 	includeVideos bool,
+	archivePath string,
 ) (instagram.CookieBundle, []instagram.Item, []string, error) {
 	names := parseNames(namesRaw)
 	cookies, warnings, err := instagram.LoadCookies(ctx, profilePath, names)
@@ -460,7 +920,7 @@ func loadHomeItems(
 		return cookies, nil, warnings, err
 	}
 
-	media, err := instagram.FetchHomeFeed(ctx, cookies, max, pageSize)
+	media, err := instagram.FetchHomeFeed(ctx, cookies, limit, pageSize)
 	if err != nil {
 		if len(media) == 0 {
 			return cookies, nil, warnings, err
@@ -468,14 +928,64 @@ func loadHomeItems(
 		warnings = append(warnings, fmt.Sprintf("home feed warning: %s", err.Error()))
 	}
 
+	if err := archiveMedia(ctx, archivePath, media); err != nil {
+		return cookies, nil, warnings, err
+	}
+
 	profile := instagram.Profile{Media: media}
 	items := instagram.BuildItems(profile, false, includeVideos)
-	if max > 0 && len(items) > max {
-		items = items[:max]
-	}
+	items = limit.ApplyItems(items)
 	return cookies, items, warnings, nil
 }
 
+func toOutputItem(item instagram.Item) outputItem {
+	return outputItem{
+		URL:           item.URL,
+		Kind:          item.Kind,
+		IsVideo:       item.IsVideo,
+		VideoURL:      item.VideoURL,
+		VideoDuration: item.VideoDuration,
+		Width:         item.Width,
+		Height:        item.Height,
+		Shortcode:     item.Shortcode,
+		TakenAt:       item.TakenAt,
+		Username:      item.Username,
+		Caption:       item.Caption,
+		ExpiringAt:    item.ExpiringAt,
+		Highlight:     item.Highlight,
+		PlayCount:     item.PlayCount,
+		ViewCount:     item.ViewCount,
+		AudioTitle:    item.AudioTitle,
+		AudioArtist:   item.AudioArtist,
+		TaggedAccount: item.TaggedAccount,
+
+		MediaID:              item.MediaID,
+		ProductType:          item.ProductType,
+		Pinned:               item.Pinned,
+		LikeCount:            item.LikeCount,
+		CommentCount:         item.CommentCount,
+		Location:             toOutputLocation(item.Location),
+		AccessibilityCaption: item.AccessibilityCaption,
+		TaggedUsers:          item.TaggedUsers,
+		Coauthors:            item.Coauthors,
+		CarouselIndex:        item.CarouselIndex,
+		Hashtags:             item.Hashtags(),
+		Mentions:             item.Mentions(),
+	}
+}
+
+func toOutputLocation(location *instagram.Location) *outputLocation {
+	if location == nil {
+		return nil
+	}
+	return &outputLocation{
+		ID:   location.ID,
+		Name: location.Name,
+		Lat:  location.Lat,
+		Lng:  location.Lng,
+	}
+}
+
 func parseNames(raw string) []string {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
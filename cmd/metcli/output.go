@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/steipete/metcli/internal/inline"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// resolveFormat applies the --inline/--url/--json shorthands and resolves
+// "auto" to inline on capable terminals and url otherwise. "jsonl" prints one
+// JSON object per line; "csv" and "tsv" print one row per file; "template"
+// executes --template per item.
+func resolveFormat(format string, inlineFlag, urlFlag, jsonFlag bool) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if inlineFlag {
+		format = "inline"
+	}
+	if urlFlag {
+		format = "url"
+	}
+	if jsonFlag {
+		format = "json"
+	}
+	if format == "auto" {
+		if isTerminal(os.Stdout) && inline.Detect() != inline.ProtocolNone {
+			format = "inline"
+		} else {
+			format = "url"
+		}
+	}
+	switch format {
+	case "inline", "url", "json", "jsonl", "csv", "tsv", "template":
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeItems prints items in the resolved format. username is sent as the
+// Referer when images are fetched for inline rendering.
+func writeItems(
+	format string,
+	items []instagram.Item,
+	username string,
+	cookies instagram.CookieBundle,
+	grid gridOptions,
+) error {
+	switch format {
+	case "json":
+		byPost, err := groupByPost(grid.Group)
+		if err != nil {
+			return err
+		}
+		var payload any
+		if byPost {
+			payload = toOutputPosts(items)
+		} else {
+			files := make([]outputItem, 0, len(items))
+			for _, item := range items {
+				files = append(files, toOutputItem(item))
+			}
+			payload = files
+		}
+		encoded, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(os.Stdout, string(encoded))
+	case "jsonl":
+		byPost, err := groupByPost(grid.Group)
+		if err != nil {
+			return err
+		}
+		w := newJSONLWriter(os.Stdout, byPost)
+		for _, item := range items {
+			if err := w.Write(item); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	case "csv", "tsv":
+		return writeTable(os.Stdout, format, items, grid.Columns)
+	case "template":
+		return writeTemplate(os.Stdout, grid.Template, items)
+	case "url":
+		for _, item := range items {
+			_, _ = fmt.Fprintln(os.Stdout, item.DownloadURL())
+		}
+	case "inline":
+		renderGrid(items, username, cookies, grid)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	return nil
+}
+
+// groupByPost validates --group and reports whether output nests by post.
+func groupByPost(group string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(group)) {
+	case "", "files":
+		return false, nil
+	case "posts":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported group: %s", group)
+	}
+}
+
+// jsonlWriter prints one JSON object per line as items arrive, so output can
+// be piped while a crawl is still running. Grouped by post, it holds a
+// post's files back until the next post starts.
+type jsonlWriter struct {
+	enc     *json.Encoder
+	byPost  bool
+	pending []instagram.Item
+}
+
+func newJSONLWriter(w io.Writer, byPost bool) *jsonlWriter {
+	return &jsonlWriter{enc: json.NewEncoder(w), byPost: byPost}
+}
+
+func (w *jsonlWriter) Write(item instagram.Item) error {
+	if !w.byPost {
+		return w.enc.Encode(toOutputItem(item))
+	}
+	if len(w.pending) > 0 && instagram.PostKey(w.pending[0].MediaItem) != instagram.PostKey(item.MediaItem) {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	w.pending = append(w.pending, item)
+	return nil
+}
+
+// Flush writes the post held back, if any.
+func (w *jsonlWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	for _, post := range toOutputPosts(w.pending) {
+		if err := w.enc.Encode(post); err != nil {
+			return err
+		}
+	}
+	w.pending = w.pending[:0]
+	return nil
+}
+
+// writeRecords prints records as one indented JSON array, or as JSON Lines.
+func writeRecords[T any](format string, records []T) error {
+	if format != "jsonl" {
+		return writeJSON(records)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toOutputPosts nests items under the post they belong to, keeping carousel
+// children in order.
+func toOutputPosts(items []instagram.Item) []outputPost {
+	media := make([]instagram.MediaItem, 0, len(items))
+	kinds := map[string]string{}
+	for _, item := range items {
+		media = append(media, item.MediaItem)
+		if _, ok := kinds[instagram.PostKey(item.MediaItem)]; !ok {
+			kinds[instagram.PostKey(item.MediaItem)] = item.Kind
+		}
+	}
+	posts := instagram.GroupPosts(media)
+	out := make([]outputPost, 0, len(posts))
+	for _, post := range posts {
+		entry := outputPost{
+			Shortcode: post.Shortcode,
+			Username:  post.Username,
+			TakenAt:   post.TakenAt,
+			Caption:   post.Caption,
+			Children:  make([]outputItem, 0, len(post.Children)),
+		}
+		for _, child := range post.Children {
+			kind := kinds[instagram.PostKey(child)]
+			entry.Children = append(entry.Children, toOutputItem(instagram.Item{Kind: kind, MediaItem: child}))
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// tableColumn is one csv/tsv column, derived from outputItem so the table
+// and the JSON output never disagree on a field.
+type tableColumn struct {
+	Name  string
+	Value func(item outputItem) string
+}
+
+// tableColumns is the stable column order of csv/tsv output. New fields are
+// appended so existing spreadsheets keep their layout.
+var tableColumns = []tableColumn{
+	{"url", func(item outputItem) string { return item.URL }},
+	{"kind", func(item outputItem) string { return item.Kind }},
+	{"is_video", func(item outputItem) string { return strconv.FormatBool(item.IsVideo) }},
+	{"video_url", func(item outputItem) string { return item.VideoURL }},
+	{"video_duration", func(item outputItem) string { return formatFloat(item.VideoDuration) }},
+	{"width", func(item outputItem) string { return formatInt(int64(item.Width)) }},
+	{"height", func(item outputItem) string { return formatInt(int64(item.Height)) }},
+	{"shortcode", func(item outputItem) string { return item.Shortcode }},
+	{"carousel_index", func(item outputItem) string { return formatInt(int64(item.CarouselIndex)) }},
+	{"media_id", func(item outputItem) string { return item.MediaID }},
+	{"taken_at", func(item outputItem) string { return formatInt(item.TakenAt) }},
+	{"taken_at_rfc3339", func(item outputItem) string { return formatRFC3339(item.TakenAt) }},
+	{"username", func(item outputItem) string { return item.Username }},
+	{"caption", func(item outputItem) string { return item.Caption }},
+	{"product_type", func(item outputItem) string { return item.ProductType }},
+	{"pinned", func(item outputItem) string { return strconv.FormatBool(item.Pinned) }},
+	{"like_count", func(item outputItem) string { return formatInt(item.LikeCount) }},
+	{"comment_count", func(item outputItem) string { return formatInt(item.CommentCount) }},
+	{"play_count", func(item outputItem) string { return formatInt(item.PlayCount) }},
+	{"view_count", func(item outputItem) string { return formatInt(item.ViewCount) }},
+	{"location_id", func(item outputItem) string {
+		if item.Location == nil {
+			return ""
+		}
+		return item.Location.ID
+	}},
+	{"location_name", func(item outputItem) string {
+		if item.Location == nil {
+			return ""
+		}
+		return item.Location.Name
+	}},
+	{"location_lat", func(item outputItem) string {
+		if item.Location == nil {
+			return ""
+		}
+		return formatFloat(item.Location.Lat)
+	}},
+	{"location_lng", func(item outputItem) string {
+		if item.Location == nil {
+			return ""
+		}
+		return formatFloat(item.Location.Lng)
+	}},
+	{"accessibility_caption", func(item outputItem) string { return item.AccessibilityCaption }},
+	{"tagged_users", func(item outputItem) string { return strings.Join(item.TaggedUsers, ",") }},
+	{"coauthors", func(item outputItem) string { return strings.Join(item.Coauthors, ",") }},
+	{"tagged_account", func(item outputItem) string { return item.TaggedAccount }},
+	{"highlight", func(item outputItem) string { return item.Highlight }},
+	{"expiring_at", func(item outputItem) string { return formatInt(item.ExpiringAt) }},
+	{"expiring_at_rfc3339", func(item outputItem) string { return formatRFC3339(item.ExpiringAt) }},
+	{"audio_title", func(item outputItem) string { return item.AudioTitle }},
+	{"audio_artist", func(item outputItem) string { return item.AudioArtist }},
+	{"hashtags", func(item outputItem) string { return strings.Join(item.Hashtags, ",") }},
+	{"mentions", func(item outputItem) string { return strings.Join(item.Mentions, ",") }},
+}
+
+// selectColumns resolves a comma-separated --columns list. An empty list
+// selects every column.
+func selectColumns(raw string) ([]tableColumn, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return tableColumns, nil
+	}
+	byName := make(map[string]tableColumn, len(tableColumns))
+	for _, column := range tableColumns {
+		byName[column.Name] = column
+	}
+	var selected []tableColumn
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			names := make([]string, 0, len(tableColumns))
+			for _, column := range tableColumns {
+				names = append(names, column.Name)
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		selected = append(selected, column)
+	}
+	if len(selected) == 0 {
+		return tableColumns, nil
+	}
+	return selected, nil
+}
+
+// writeTable prints items as csv or tsv with a header row. Fields holding
+// separators, quotes or newlines are quoted, so multi-line captions stay in
+// one cell.
+func writeTable(w io.Writer, format string, items []instagram.Item, columns []tableColumn) error {
+	if len(columns) == 0 {
+		columns = tableColumns
+	}
+	out := csv.NewWriter(w)
+	if format == "tsv" {
+		out.Comma = '\t'
+	}
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(columns))
+	for _, item := range items {
+		entry := toOutputItem(item)
+		for i, column := range columns {
+			row[i] = column.Value(entry)
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func formatInt(value int64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+func formatFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatRFC3339(unix int64) string {
+	if unix <= 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
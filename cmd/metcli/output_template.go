@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// TemplateFlags are shared by the commands whose items go through
+// writeItems, for --format template.
+type TemplateFlags struct {
+	Template     string `help:"Go text/template applied to each item with --format template"`
+	TemplateFile string `help:"file holding the template for --format template" type:"path"`
+}
+
+// parse compiles the template when format is "template" and returns nil
+// otherwise.
+func (flags TemplateFlags) parse(format string) (*template.Template, error) {
+	if format != "template" {
+		return nil, nil
+	}
+	text, name := flags.Template, "template"
+	if flags.TemplateFile != "" {
+		if text != "" {
+			return nil, fmt.Errorf("use either --template or --template-file")
+		}
+		data, err := os.ReadFile(flags.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("read template: %w", err)
+		}
+		text, name = string(data), filepath.Base(flags.TemplateFile)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("--format template needs --template or --template-file")
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateFuncs are the helpers available to --template, next to the fields
+// and methods of instagram.Item.
+var templateFuncs = template.FuncMap{
+	// time formats a unix timestamp with a Go layout in local time.
+	"time": func(layout string, unix int64) string {
+		if unix <= 0 {
+			return ""
+		}
+		return time.Unix(unix, 0).Format(layout)
+	},
+	"rfc3339":   formatRFC3339,
+	"permalink": permalink,
+	"truncate":  truncate,
+	"oneline":   compactWhitespace,
+	"join":      strings.Join,
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// writeTemplate executes tmpl once per item, ending each result with a
+// newline unless the template already does.
+func writeTemplate(w io.Writer, tmpl *template.Template, items []instagram.Item) error {
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, item); err != nil {
+			return fmt.Errorf("execute template: %w", err)
+		}
+		if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// permalink is the post page of a shortcode.
+func permalink(shortcode string) string {
+	if shortcode == "" {
+		return ""
+	}
+	return "https://www.instagram.com/p/" + shortcode + "/"
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+// The argument order allows {{.Caption | truncate 40}}.
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func TestJSONLWriterGroupsPosts(t *testing.T) {
+	items := []instagram.Item{
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{Shortcode: "A", URL: "https://x/a1.jpg", CarouselIndex: 1}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{Shortcode: "A", URL: "https://x/a2.jpg", CarouselIndex: 2}},
+		{Kind: instagram.KindMedia, MediaItem: instagram.MediaItem{Shortcode: "B", URL: "https://x/b.jpg"}},
+	}
+
+	var files bytes.Buffer
+	w := newJSONLWriter(&files, false)
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if lines := strings.Count(files.String(), "\n"); lines != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", lines, files.String())
+	}
+
+	var posts bytes.Buffer
+	w = newJSONLWriter(&posts, true)
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if lines := strings.Count(posts.String(), "\n"); lines != 1 {
+		t.Fatalf("expected first post flushed on second, got %d lines", lines)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	out := strings.Split(strings.TrimSpace(posts.String()), "\n")
+	if len(out) != 2 || !strings.Contains(out[0], `"shortcode":"A"`) || !strings.Contains(out[1], `"shortcode":"B"`) {
+		t.Fatalf("unexpected grouped output: %s", posts.String())
+	}
+	if !strings.Contains(out[0], "a2.jpg") {
+		t.Fatalf("expected both carousel children in first post: %s", out[0])
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	items := []instagram.Item{{
+		Kind: instagram.KindMedia,
+		MediaItem: instagram.MediaItem{
+			URL:       "https://x/a.jpg",
+			Shortcode: "A",
+			TakenAt:   1700000000,
+			Caption:   "line one\nline \"two\"",
+		},
+	}}
+	columns, err := selectColumns("shortcode, taken_at,taken_at_rfc3339,caption")
+	if err != nil {
+		t.Fatalf("selectColumns: %v", err)
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeTable(&csvOut, "csv", items, columns); err != nil {
+		t.Fatalf("writeTable: %v", err)
+	}
+	want := "shortcode,taken_at,taken_at_rfc3339,caption\n" +
+		"A,1700000000,2023-11-14T22:13:20Z,\"line one\nline \"\"two\"\"\"\n"
+	if csvOut.String() != want {
+		t.Fatalf("unexpected csv:\n%s", csvOut.String())
+	}
+
+	var tsvOut bytes.Buffer
+	if err := writeTable(&tsvOut, "tsv", items, columns[:2]); err != nil {
+		t.Fatalf("writeTable: %v", err)
+	}
+	if tsvOut.String() != "shortcode\ttaken_at\nA\t1700000000\n" {
+		t.Fatalf("unexpected tsv: %q", tsvOut.String())
+	}
+
+	if _, err := selectColumns("shortcode,nope"); err == nil {
+		t.Fatalf("expected unknown column error")
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	tmpl, err := TemplateFlags{
+		Template: `{{.Username}} {{permalink .Shortcode}} {{rfc3339 .TakenAt}} {{.Caption | oneline | truncate 8}}`,
+	}.parse("template")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	items := []instagram.Item{{
+		Kind: instagram.KindMedia,
+		MediaItem: instagram.MediaItem{
+			Username:  "alice",
+			Shortcode: "A",
+			TakenAt:   1700000000,
+			Caption:   "hello\nworld again",
+		},
+	}}
+	var out bytes.Buffer
+	if err := writeTemplate(&out, tmpl, items); err != nil {
+		t.Fatalf("writeTemplate: %v", err)
+	}
+	want := "alice https://www.instagram.com/p/A/ 2023-11-14T22:13:20Z hello w…\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	if tmpl, err := (TemplateFlags{}).parse("json"); tmpl != nil || err != nil {
+		t.Fatalf("expected no template for json, got %v %v", tmpl, err)
+	}
+	if _, err := (TemplateFlags{}).parse("template"); err == nil {
+		t.Fatalf("expected error without --template")
+	}
+}
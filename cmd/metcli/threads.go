@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/steipete/metcli/internal/inline"
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+type outputThreadsPost struct {
+	ID         string       `json:"id"`
+	Shortcode  string       `json:"shortcode,omitempty"`
+	URL        string       `json:"url,omitempty"`
+	Username   string       `json:"username"`
+	Text       string       `json:"text,omitempty"`
+	TakenAt    int64        `json:"taken_at,omitempty"`
+	LikeCount  int64        `json:"like_count,omitempty"`
+	ReplyCount int64        `json:"reply_count,omitempty"`
+	ReplyTo    string       `json:"reply_to,omitempty"`
+	LinkURL    string       `json:"link_url,omitempty"`
+	Media      []outputItem `json:"media,omitempty"`
+}
+
+type outputThreadsConversation struct {
+	Post    outputThreadsPost   `json:"post"`
+	Replies []outputThreadsPost `json:"replies"`
+}
+
+// threadsOutput holds the flags shared by the threads commands.
+type threadsOutput struct {
+	format        string
+	includeVideos bool
+	thumbCols     int
+	cookies       instagram.CookieBundle
+}
+
+func (cmd *ThreadsPostsCmd) Run() error {
+	username := instagram.ParseThreadsUsername(cmd.User)
+	if username == "" {
+		return fmt.Errorf("username required")
+	}
+	format, err := resolveThreadsFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	profile, err := instagram.FetchProfile(ctx, username, cookies)
+	if err != nil {
+		return err
+	}
+	posts, err := instagram.FetchThreads(ctx, username, profile.UserID, cookies, cmd.Replies, cmd.Max)
+	if err != nil {
+		if len(posts) == 0 {
+			return err
+		}
+		printWarnings("[metcli]", []string{"threads warning: " + err.Error()})
+	}
+	if len(posts) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no threads found")
+		return nil
+	}
+
+	out := threadsOutput{format: format, includeVideos: cmd.IncludeVideos, thumbCols: cmd.ThumbCols, cookies: cookies}
+	if format == "json" || format == "jsonl" {
+		payload := make([]outputThreadsPost, 0, len(posts))
+		for _, post := range posts {
+			payload = append(payload, out.toOutput(post))
+		}
+		return writeRecords(format, payload)
+	}
+	return out.write(ctx, posts)
+}
+
+func (cmd *ThreadsPostCmd) Run() error {
+	shortcode := instagram.ParseShortcode(cmd.Post)
+	if shortcode == "" {
+		return fmt.Errorf("shortcode or post URL required")
+	}
+	format, err := resolveThreadsFormat(cmd.Format, cmd.Inline, cmd.URL, cmd.JSON)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cookies, warnings, err := instagram.LoadCookies(ctx, cmd.Profile, parseNames(cmd.Names))
+	if err != nil {
+		return err
+	}
+	printWarnings("[metcli]", warnings)
+
+	post, replies, err := instagram.FetchThreadsPost(ctx, shortcode, cookies, cmd.Max)
+	if err != nil {
+		if post.ID == "" {
+			return err
+		}
+		printWarnings("[metcli]", []string{"threads replies warning: " + err.Error()})
+	}
+
+	out := threadsOutput{format: format, includeVideos: cmd.IncludeVideos, thumbCols: cmd.ThumbCols, cookies: cookies}
+	if format == "jsonl" {
+		payload := make([]outputThreadsPost, 0, len(replies)+1)
+		payload = append(payload, out.toOutput(post))
+		for _, reply := range replies {
+			payload = append(payload, out.toOutput(reply))
+		}
+		return writeRecords(format, payload)
+	}
+	if format == "json" {
+		payload := outputThreadsConversation{
+			Post:    out.toOutput(post),
+			Replies: make([]outputThreadsPost, 0, len(replies)),
+		}
+		for _, reply := range replies {
+			payload.Replies = append(payload.Replies, out.toOutput(reply))
+		}
+		return writeJSON(payload)
+	}
+	return out.write(ctx, append([]instagram.ThreadsPost{post}, replies...))
+}
+
+// resolveThreadsFormat is resolveFormat with a text transcript in place of
+// the image-only grid, since most threads have no media.
+func resolveThreadsFormat(format string, inlineFlag, urlFlag, jsonFlag bool) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "text" && !inlineFlag && !urlFlag && !jsonFlag {
+		return format, nil
+	}
+	if format == "auto" && !inlineFlag && !urlFlag && !jsonFlag {
+		if !isTerminal(os.Stdout) || inline.Detect() == inline.ProtocolNone {
+			return "text", nil
+		}
+	}
+	format, err := resolveFormat(format, inlineFlag, urlFlag, jsonFlag)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case "csv", "tsv", "template":
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+	return format, nil
+}
+
+func (out threadsOutput) media(post instagram.ThreadsPost) []instagram.MediaItem {
+	if out.includeVideos {
+		return post.Media
+	}
+	media := make([]instagram.MediaItem, 0, len(post.Media))
+	for _, item := range post.Media {
+		if !item.IsVideo {
+			media = append(media, item)
+		}
+	}
+	return media
+}
+
+func (out threadsOutput) toOutput(post instagram.ThreadsPost) outputThreadsPost {
+	result := outputThreadsPost{
+		ID:         post.ID,
+		Shortcode:  post.Code,
+		URL:        post.URL(),
+		Username:   post.Username,
+		Text:       post.Text,
+		TakenAt:    post.TakenAt,
+		LikeCount:  post.LikeCount,
+		ReplyCount: post.ReplyCount,
+		ReplyTo:    post.ReplyTo,
+		LinkURL:    post.LinkURL,
+	}
+	if media := out.media(post); len(media) > 0 {
+		result.Media = mediaOutput(media)
+	}
+	return result
+}
+
+// write prints posts as text, media URLs, or text with images drawn inline
+// below each post.
+func (out threadsOutput) write(ctx context.Context, posts []instagram.ThreadsPost) error {
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	if out.format == "url" {
+		for _, post := range posts {
+			for _, item := range out.media(post) {
+				_, _ = fmt.Fprintln(writer, item.DownloadURL())
+			}
+		}
+		return nil
+	}
+
+	protocol := inline.ProtocolNone
+	if out.format == "inline" {
+		protocol = inline.Detect()
+	}
+	cols := out.thumbCols
+	if cols <= 0 {
+		cols = autoStreamCols() / 2
+	}
+	cellAspect := inline.CellAspectRatio("METCLI_CELL_ASPECT", 0.5)
+	client := instagram.ImageClient()
+	nextID := uint32(1)
+
+	for _, post := range posts {
+		writeThreadsPost(writer, post)
+		media := out.media(post)
+		if protocol == inline.ProtocolNone {
+			for _, item := range media {
+				_, _ = fmt.Fprintf(writer, "    media: %s\n", item.DownloadURL())
+			}
+			_, _ = fmt.Fprintln(writer)
+			continue
+		}
+		_, _ = fmt.Fprintln(writer)
+		for _, item := range media {
+			data, width, height, err := instagram.DownloadImage(ctx, client, item.URL, post.Username, out.cookies)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
+				continue
+			}
+			if err := sendInlineImage(writer, protocol, nextID, inlineName(item.Shortcode), data, width, height, cols, cellAspect); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
+				continue
+			}
+			nextID++
+		}
+		_ = writer.Flush()
+	}
+	return nil
+}
+
+func writeThreadsPost(w io.Writer, post instagram.ThreadsPost) {
+	header := fmt.Sprintf("[%s] @%s", formatTimestamp(post.TakenAt), post.Username)
+	if post.ReplyTo != "" {
+		header += " → @" + post.ReplyTo
+	}
+	if post.LikeCount > 0 || post.ReplyCount > 0 {
+		header += fmt.Sprintf(" (%d likes, %d replies)", post.LikeCount, post.ReplyCount)
+	}
+	_, _ = fmt.Fprintln(w, header)
+	if text := strings.TrimSpace(post.Text); text != "" {
+		_, _ = fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(text, "\n", "\n    "))
+	}
+	if post.LinkURL != "" {
+		_, _ = fmt.Fprintf(w, "    link: %s\n", post.LinkURL)
+	}
+	if url := post.URL(); url != "" {
+		_, _ = fmt.Fprintf(w, "    %s\n", url)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/inline"
+	"github.com/steipete/metcli/internal/whatsapp"
+)
+
+type outputChatMessage struct {
+	Timestamp    int64  `json:"timestamp"`
+	Time         string `json:"time"`
+	Sender       string `json:"sender,omitempty"`
+	Text         string `json:"text,omitempty"`
+	System       bool   `json:"system,omitempty"`
+	Attachment   string `json:"attachment,omitempty"`
+	MediaOmitted bool   `json:"media_omitted,omitempty"`
+}
+
+func (cmd *WhatsAppImportCmd) Run() error {
+	if strings.TrimSpace(cmd.Path) == "" {
+		return fmt.Errorf("chat export ZIP or .txt required")
+	}
+	loc := time.Local
+	if tz := strings.TrimSpace(cmd.TZ); tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("time zone: %w", err)
+		}
+	}
+
+	export, err := whatsapp.Open(cmd.Path, loc)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+
+	messages := export.Messages
+	if filter := strings.ToLower(strings.TrimSpace(cmd.Sender)); filter != "" {
+		messages = messages[:0:0]
+		for _, msg := range export.Messages {
+			if strings.Contains(strings.ToLower(msg.Sender), filter) {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	messages = firstN(messages, cmd.Max)
+	if len(messages) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "[metcli] no messages found")
+		return nil
+	}
+
+	format := strings.ToLower(strings.TrimSpace(cmd.Format))
+	if cmd.JSON {
+		format = "json"
+	}
+	if format == "auto" {
+		if isTerminal(os.Stdout) && inline.Detect() != inline.ProtocolNone {
+			format = "inline"
+		} else {
+			format = "text"
+		}
+	}
+
+	switch format {
+	case "json":
+		payload := make([]outputChatMessage, 0, len(messages))
+		for _, msg := range messages {
+			payload = append(payload, toOutputChatMessage(msg))
+		}
+		return writeJSON(payload)
+	case "jsonl":
+		encoder := json.NewEncoder(os.Stdout)
+		for _, msg := range messages {
+			if err := encoder.Encode(toOutputChatMessage(msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "text":
+		writer := bufio.NewWriter(os.Stdout)
+		defer writer.Flush()
+		writeChatHeader(writer, export.Name)
+		for _, msg := range messages {
+			writeChatMessage(writer, msg)
+		}
+		return nil
+	case "inline":
+		return cmd.writeInline(export, messages)
+	default:
+		return fmt.Errorf("unsupported format: %s", cmd.Format)
+	}
+}
+
+// writeInline prints the transcript with image attachments drawn below the
+// message that sent them.
+func (cmd *WhatsAppImportCmd) writeInline(export *whatsapp.Export, messages []whatsapp.Message) error {
+	protocol := inline.Detect()
+	cols := cmd.ThumbCols
+	if cols <= 0 {
+		cols = autoStreamCols() / 2
+	}
+	cellAspect := inline.CellAspectRatio("METCLI_CELL_ASPECT", 0.5)
+
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writeChatHeader(writer, export.Name)
+	nextID := uint32(1)
+	for _, msg := range messages {
+		writeChatMessage(writer, msg)
+		if protocol == inline.ProtocolNone || !whatsapp.IsImage(msg.Attachment) {
+			continue
+		}
+		data, err := export.ReadFile(msg.Attachment)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s\n", err.Error())
+			continue
+		}
+		if err := sendInlineImage(writer, protocol, nextID, msg.Attachment, data, 0, 0, cols, cellAspect); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "[metcli] %s: %s\n", msg.Attachment, err.Error())
+			continue
+		}
+		nextID++
+		_ = writer.Flush()
+	}
+	return nil
+}
+
+func writeChatHeader(w io.Writer, name string) {
+	if name != "" {
+		_, _ = fmt.Fprintf(w, "# %s\n\n", name)
+	}
+}
+
+func writeChatMessage(w io.Writer, msg whatsapp.Message) {
+	stamp := formatTimestamp(msg.Time.Unix())
+	text := strings.ReplaceAll(strings.TrimSpace(msg.Text), "\n", "\n    ")
+	if msg.System {
+		_, _ = fmt.Fprintf(w, "[%s] * %s\n", stamp, text)
+		return
+	}
+	if text == "" && msg.MediaOmitted {
+		text = "[media omitted]"
+	}
+	if text == "" && msg.Attachment != "" {
+		text = "[attachment]"
+	}
+	_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", stamp, msg.Sender, text)
+	if msg.Attachment != "" {
+		_, _ = fmt.Fprintf(w, "    file: %s\n", msg.Attachment)
+	}
+}
+
+func toOutputChatMessage(msg whatsapp.Message) outputChatMessage {
+	return outputChatMessage{
+		Timestamp:    msg.Time.Unix(),
+		Time:         msg.Time.Format(time.RFC3339),
+		Sender:       msg.Sender,
+		Text:         msg.Text,
+		System:       msg.System,
+		Attachment:   msg.Attachment,
+		MediaOmitted: msg.MediaOmitted,
+	}
+}
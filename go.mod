@@ -7,6 +7,7 @@ require (
 	github.com/steipete/sweetcookie v0.0.0-00010101000000-000000000000
 	golang.org/x/image v0.35.0
 	golang.org/x/term v0.33.0
+	modernc.org/sqlite v1.36.0
 )
 
 require (
@@ -26,7 +27,6 @@ require (
 	modernc.org/libc v1.61.13 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.8.2 // indirect
-	modernc.org/sqlite v1.36.0 // indirect
 )
 
 replace github.com/steipete/sweetcookie => ../gookie
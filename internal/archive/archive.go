@@ -0,0 +1,406 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+	_ "modernc.org/sqlite"
+)
+
+// Archive is a local SQLite store of profiles, media and download status.
+type Archive struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// migrations are applied in order; the schema version is the number of
+// migrations applied, tracked in PRAGMA user_version. Never edit an existing
+// entry, append a new one instead.
+var migrations = [][]string{
+	{
+		`CREATE TABLE profiles (
+			username TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			profile_pic_url TEXT NOT NULL DEFAULT '',
+			profile_pic_url_hd TEXT NOT NULL DEFAULT '',
+			first_seen_at INTEGER NOT NULL,
+			last_seen_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE media (
+			media_key TEXT PRIMARY KEY,
+			shortcode TEXT NOT NULL DEFAULT '',
+			username TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			is_video INTEGER NOT NULL DEFAULT 0,
+			taken_at INTEGER NOT NULL DEFAULT 0,
+			caption TEXT NOT NULL DEFAULT '',
+			pinned INTEGER NOT NULL DEFAULT 0,
+			first_seen_at INTEGER NOT NULL,
+			last_seen_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX media_username_taken_at ON media (username, taken_at)`,
+		`CREATE INDEX media_shortcode ON media (shortcode)`,
+		`CREATE TABLE downloads (
+			media_key TEXT PRIMARY KEY REFERENCES media (media_key) ON DELETE CASCADE,
+			path TEXT NOT NULL,
+			bytes INTEGER NOT NULL DEFAULT 0,
+			downloaded_at INTEGER NOT NULL
+		)`,
+	},
+	{
+		`ALTER TABLE media ADD COLUMN video_url TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN video_duration REAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN width INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN height INTEGER NOT NULL DEFAULT 0`,
+	},
+	{
+		`ALTER TABLE media ADD COLUMN play_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN view_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN audio_title TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN audio_artist TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`ALTER TABLE media ADD COLUMN media_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN comment_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN location_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN location_name TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN location_lat REAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN location_lng REAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE media ADD COLUMN accessibility_caption TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN tagged_users TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN coauthors TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE media ADD COLUMN product_type TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`ALTER TABLE media ADD COLUMN carousel_index INTEGER NOT NULL DEFAULT 0`,
+	},
+}
+
+// Open opens (creating if needed) the archive at path and migrates it to the
+// current schema.
+func Open(ctx context.Context, path string) (*Archive, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("archive path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// A single connection keeps PRAGMAs in effect and avoids SQLITE_BUSY
+	// between our own statements.
+	db.SetMaxOpenConns(1)
+	for _, pragma := range []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA busy_timeout = 5000",
+		"PRAGMA journal_mode = WAL",
+	} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("archive %s: %w", pragma, err)
+		}
+	}
+
+	a := &Archive{db: db, now: time.Now}
+	if err := a.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
+
+// SchemaVersion reports how many migrations have been applied.
+func (a *Archive) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := a.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (a *Archive) migrate(ctx context.Context) error {
+	version, err := a.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("archive schema version %d is newer than this metcli (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		tx, err := a.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range migrations[i] {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("archive migration %d: %w", i+1, err)
+			}
+		}
+		// PRAGMA does not accept bound parameters.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertProfile records profile and its media. Empty fields never overwrite
+// values already stored.
+func (a *Archive) UpsertProfile(ctx context.Context, profile instagram.Profile) error {
+	username := strings.TrimSpace(profile.Username)
+	if username == "" {
+		return fmt.Errorf("profile username is required")
+	}
+	now := a.now().Unix()
+	_, err := a.db.ExecContext(ctx, `
+		INSERT INTO profiles (username, user_id, profile_pic_url, profile_pic_url_hd, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (username) DO UPDATE SET
+			user_id = CASE WHEN excluded.user_id != '' THEN excluded.user_id ELSE profiles.user_id END,
+			profile_pic_url = CASE WHEN excluded.profile_pic_url != '' THEN excluded.profile_pic_url ELSE profiles.profile_pic_url END,
+			profile_pic_url_hd = CASE WHEN excluded.profile_pic_url_hd != '' THEN excluded.profile_pic_url_hd ELSE profiles.profile_pic_url_hd END,
+			last_seen_at = excluded.last_seen_at`,
+		username,
+		strings.TrimSpace(profile.UserID),
+		strings.TrimSpace(profile.ProfilePicURL),
+		strings.TrimSpace(profile.ProfilePicURLHD),
+		now,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	return a.UpsertMedia(ctx, profile.Media)
+}
+
+// UpsertMedia records media items, refreshing the URL and last-seen time of
+// items already in the archive.
+func (a *Archive) UpsertMedia(ctx context.Context, media []instagram.MediaItem) error {
+	if len(media) == 0 {
+		return nil
+	}
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	now := a.now().Unix()
+	for _, item := range media {
+		if err := upsertMedia(ctx, tx, item, now); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MarkDownloaded records that item was saved to path.
+func (a *Archive) MarkDownloaded(ctx context.Context, item instagram.MediaItem, path string, bytes int64) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	now := a.now().Unix()
+	if err := upsertMedia(ctx, tx, item, now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO downloads (media_key, path, bytes, downloaded_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (media_key) DO UPDATE SET
+			path = excluded.path,
+			bytes = excluded.bytes,
+			downloaded_at = excluded.downloaded_at`,
+		instagram.MediaKey(item),
+		path,
+		bytes,
+		now,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Media lists archived media for username, newest first. An empty username
+// lists everything.
+func (a *Archive) Media(ctx context.Context, username string) ([]instagram.MediaItem, error) {
+	query := `SELECT url, is_video, shortcode, taken_at, username, caption, pinned,
+		video_url, video_duration, width, height, play_count, view_count, audio_title, audio_artist,
+		media_id, like_count, comment_count, location_id, location_name, location_lat, location_lng,
+		accessibility_caption, tagged_users, coauthors, product_type, carousel_index FROM media`
+	args := []any{}
+	if strings.TrimSpace(username) != "" {
+		query += ` WHERE username = ?`
+		args = append(args, strings.TrimSpace(username))
+	}
+	query += ` ORDER BY taken_at DESC, shortcode, carousel_index, media_key`
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []instagram.MediaItem
+	for rows.Next() {
+		var item instagram.MediaItem
+		var location instagram.Location
+		var tagged, coauthors string
+		if err := rows.Scan(
+			&item.URL,
+			&item.IsVideo,
+			&item.Shortcode,
+			&item.TakenAt,
+			&item.Username,
+			&item.Caption,
+			&item.Pinned,
+			&item.VideoURL,
+			&item.VideoDuration,
+			&item.Width,
+			&item.Height,
+			&item.PlayCount,
+			&item.ViewCount,
+			&item.AudioTitle,
+			&item.AudioArtist,
+			&item.MediaID,
+			&item.LikeCount,
+			&item.CommentCount,
+			&location.ID,
+			&location.Name,
+			&location.Lat,
+			&location.Lng,
+			&item.AccessibilityCaption,
+			&tagged,
+			&coauthors,
+			&item.ProductType,
+			&item.CarouselIndex,
+		); err != nil {
+			return nil, err
+		}
+		if location.Name != "" {
+			item.Location = &location
+		}
+		item.TaggedUsers = splitNames(tagged)
+		item.Coauthors = splitNames(coauthors)
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// DownloadPath returns where item was saved, or "" if it never was.
+func (a *Archive) DownloadPath(ctx context.Context, item instagram.MediaItem) (string, error) {
+	var path string
+	err := a.db.QueryRowContext(ctx, `SELECT path FROM downloads WHERE media_key = ?`, instagram.MediaKey(item)).Scan(&path)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return path, err
+}
+
+func upsertMedia(ctx context.Context, tx *sql.Tx, item instagram.MediaItem, now int64) error {
+	if strings.TrimSpace(item.URL) == "" {
+		return nil
+	}
+	var location instagram.Location
+	if item.Location != nil {
+		location = *item.Location
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO media (media_key, shortcode, username, url, is_video, taken_at, caption, pinned,
+			video_url, video_duration, width, height, play_count, view_count, audio_title, audio_artist,
+			media_id, like_count, comment_count, location_id, location_name, location_lat, location_lng,
+			accessibility_caption, tagged_users, coauthors, product_type, carousel_index,
+			first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (media_key) DO UPDATE SET
+			url = excluded.url,
+			video_url = CASE WHEN excluded.video_url != '' THEN excluded.video_url ELSE media.video_url END,
+			video_duration = CASE WHEN excluded.video_duration != 0 THEN excluded.video_duration ELSE media.video_duration END,
+			width = CASE WHEN excluded.width != 0 THEN excluded.width ELSE media.width END,
+			height = CASE WHEN excluded.height != 0 THEN excluded.height ELSE media.height END,
+			play_count = CASE WHEN excluded.play_count != 0 THEN excluded.play_count ELSE media.play_count END,
+			view_count = CASE WHEN excluded.view_count != 0 THEN excluded.view_count ELSE media.view_count END,
+			audio_title = CASE WHEN excluded.audio_title != '' THEN excluded.audio_title ELSE media.audio_title END,
+			audio_artist = CASE WHEN excluded.audio_artist != '' THEN excluded.audio_artist ELSE media.audio_artist END,
+			media_id = CASE WHEN excluded.media_id != '' THEN excluded.media_id ELSE media.media_id END,
+			like_count = CASE WHEN excluded.like_count != 0 THEN excluded.like_count ELSE media.like_count END,
+			comment_count = CASE WHEN excluded.comment_count != 0 THEN excluded.comment_count ELSE media.comment_count END,
+			location_id = CASE WHEN excluded.location_name != '' THEN excluded.location_id ELSE media.location_id END,
+			location_lat = CASE WHEN excluded.location_name != '' THEN excluded.location_lat ELSE media.location_lat END,
+			location_lng = CASE WHEN excluded.location_name != '' THEN excluded.location_lng ELSE media.location_lng END,
+			location_name = CASE WHEN excluded.location_name != '' THEN excluded.location_name ELSE media.location_name END,
+			accessibility_caption = CASE WHEN excluded.accessibility_caption != '' THEN excluded.accessibility_caption ELSE media.accessibility_caption END,
+			tagged_users = CASE WHEN excluded.tagged_users != '' THEN excluded.tagged_users ELSE media.tagged_users END,
+			coauthors = CASE WHEN excluded.coauthors != '' THEN excluded.coauthors ELSE media.coauthors END,
+			product_type = CASE WHEN excluded.product_type != '' THEN excluded.product_type ELSE media.product_type END,
+			carousel_index = CASE WHEN excluded.carousel_index != 0 THEN excluded.carousel_index ELSE media.carousel_index END,
+			username = CASE WHEN excluded.username != '' THEN excluded.username ELSE media.username END,
+			taken_at = CASE WHEN excluded.taken_at != 0 THEN excluded.taken_at ELSE media.taken_at END,
+			caption = CASE WHEN excluded.caption != '' THEN excluded.caption ELSE media.caption END,
+			pinned = excluded.pinned,
+			last_seen_at = excluded.last_seen_at`,
+		instagram.MediaKey(item),
+		strings.TrimSpace(item.Shortcode),
+		strings.TrimSpace(item.Username),
+		item.URL,
+		item.IsVideo,
+		item.TakenAt,
+		item.Caption,
+		item.Pinned,
+		item.VideoURL,
+		item.VideoDuration,
+		item.Width,
+		item.Height,
+		item.PlayCount,
+		item.ViewCount,
+		item.AudioTitle,
+		item.AudioArtist,
+		item.MediaID,
+		item.LikeCount,
+		item.CommentCount,
+		location.ID,
+		location.Name,
+		location.Lat,
+		location.Lng,
+		item.AccessibilityCaption,
+		strings.Join(item.TaggedUsers, ","),
+		strings.Join(item.Coauthors, ","),
+		item.ProductType,
+		item.CarouselIndex,
+		now,
+		now,
+	)
+	return err
+}
+
+// splitNames reverses the comma join usernames are stored with.
+func splitNames(joined string) []string {
+	if joined == "" {
+		return nil
+	}
+	return strings.Split(joined, ",")
+}
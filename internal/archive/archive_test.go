@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+func openTestArchive(t *testing.T) *Archive {
+	t.Helper()
+	a, err := Open(context.Background(), filepath.Join(t.TempDir(), "nested", "archive.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
+
+func TestOpenMigratesAndReopens(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "archive.db")
+	a, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	version, err := a.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if version != len(migrations) {
+		t.Fatalf("expected schema version %d, got %d", len(migrations), version)
+	}
+	_ = a.Close()
+
+	a, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	_ = a.Close()
+}
+
+func TestUpsertProfileAndMedia(t *testing.T) {
+	ctx := context.Background()
+	a := openTestArchive(t)
+	a.now = func() time.Time { return time.Unix(100, 0) }
+
+	profile := instagram.Profile{
+		Username: "tester",
+		UserID:   "42",
+		Media: []instagram.MediaItem{
+			{
+				URL: "https://cdn/x/1_n.jpg?sig=a", Shortcode: "old", TakenAt: 10, Username: "tester", Caption: "first",
+				LikeCount: 5, Location: &instagram.Location{ID: "7", Name: "Vienna", Lat: 48.2, Lng: 16.37},
+				TaggedUsers: []string{"a", "b"},
+			},
+			{URL: "https://cdn/x/2_n.jpg?sig=a", Shortcode: "new", TakenAt: 20, Username: "tester"},
+		},
+	}
+	if err := a.UpsertProfile(ctx, profile); err != nil {
+		t.Fatalf("UpsertProfile: %v", err)
+	}
+
+	// Same files with fresh signatures and no caption must update in place.
+	refetched := []instagram.MediaItem{
+		{URL: "https://cdn/x/1_n.jpg?sig=b", Shortcode: "old", TakenAt: 10, Username: "tester"},
+	}
+	if err := a.UpsertMedia(ctx, refetched); err != nil {
+		t.Fatalf("UpsertMedia: %v", err)
+	}
+
+	media, err := a.Media(ctx, "tester")
+	if err != nil {
+		t.Fatalf("Media: %v", err)
+	}
+	if len(media) != 2 {
+		t.Fatalf("expected 2 media rows, got %d", len(media))
+	}
+	if media[0].Shortcode != "new" || media[1].Shortcode != "old" {
+		t.Fatalf("expected newest first, got %q, %q", media[0].Shortcode, media[1].Shortcode)
+	}
+	if media[1].URL != "https://cdn/x/1_n.jpg?sig=b" || media[1].Caption != "first" {
+		t.Fatalf("expected refreshed url and kept caption, got %q / %q", media[1].URL, media[1].Caption)
+	}
+	if media[1].LikeCount != 5 || media[1].Location == nil || media[1].Location.Name != "Vienna" || len(media[1].TaggedUsers) != 2 {
+		t.Fatalf("expected kept engagement, location and tags, got %+v", media[1])
+	}
+}
+
+func TestMarkDownloaded(t *testing.T) {
+	ctx := context.Background()
+	a := openTestArchive(t)
+	item := instagram.MediaItem{URL: "https://cdn/x/3_n.jpg", Shortcode: "abc", Username: "tester"}
+
+	path, err := a.DownloadPath(ctx, item)
+	if err != nil || path != "" {
+		t.Fatalf("expected no download yet, got %q (%v)", path, err)
+	}
+	if err := a.MarkDownloaded(ctx, item, "/tmp/abc.jpg", 12); err != nil {
+		t.Fatalf("MarkDownloaded: %v", err)
+	}
+	path, err = a.DownloadPath(ctx, item)
+	if err != nil || path != "/tmp/abc.jpg" {
+		t.Fatalf("expected recorded path, got %q (%v)", path, err)
+	}
+}
@@ -0,0 +1,234 @@
+// Package dyi reads Meta "Download Your Information" exports offline.
+package dyi
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// rawMedia is a photo or video reference, the same in every Meta export.
+type rawMedia struct {
+	URI               string `json:"uri"`
+	CreationTimestamp int64  `json:"creation_timestamp"`
+	Title             string `json:"title"`
+	Description       string `json:"description"`
+}
+
+// Export is an unpacked view of a DYI archive, read either straight from the
+// ZIP or from a directory it was extracted to.
+type Export struct {
+	fsys   fs.FS
+	closer io.Closer
+	files  []string
+}
+
+// Open opens the export at path, a .zip file or an extracted directory.
+func Open(path string) (*Export, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return newExport(os.DirFS(path), nil)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open export %s: %w", path, err)
+	}
+	export, err := newExport(zr, zr)
+	if err != nil {
+		_ = zr.Close()
+		return nil, err
+	}
+	return export, nil
+}
+
+func newExport(fsys fs.FS, closer io.Closer) (*Export, error) {
+	var files []string
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			files = append(files, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return &Export{fsys: fsys, closer: closer, files: files}, nil
+}
+
+func (e *Export) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// ReadFile reads a file of the export. Media URIs in the export's JSON are
+// relative to its root, and some exports nest everything one folder deeper,
+// so name is also tried as a suffix.
+func (e *Export) ReadFile(name string) ([]byte, error) {
+	name = strings.TrimPrefix(path.Clean(strings.TrimSpace(name)), "/")
+	if data, err := fs.ReadFile(e.fsys, name); err == nil {
+		return data, nil
+	}
+	for _, file := range e.files {
+		if strings.HasSuffix(file, "/"+name) {
+			return fs.ReadFile(e.fsys, file)
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+}
+
+// find lists the files whose path matches, in name order.
+func (e *Export) find(match func(name string) bool) []string {
+	var out []string
+	for _, file := range e.files {
+		if match(file) {
+			out = append(out, file)
+		}
+	}
+	return out
+}
+
+func (e *Export) decode(name string, v any) error {
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// FixText undoes the mojibake in DYI JSON: Meta writes each UTF-8 byte as its
+// own \u00XX escape, so "é" arrives as "Ã©". Strings that are not such
+// double encodings are returned unchanged.
+func FixText(s string) string {
+	if s == "" {
+		return s
+	}
+	raw := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			return s
+		}
+		raw = append(raw, byte(r))
+	}
+	if !utf8.Valid(raw) {
+		return s
+	}
+	return string(raw)
+}
+
+// matchBase reports whether the base name of file is base, or base with a
+// numeric "_N" suffix for exports split into several parts.
+func matchBase(file, base string) bool {
+	name := path.Base(file)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if name == base {
+		return true
+	}
+	if !strings.HasPrefix(name, stem+"_") || path.Ext(name) != ext {
+		return false
+	}
+	suffix := strings.TrimSuffix(strings.TrimPrefix(name, stem+"_"), ext)
+	if suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// matchData reports whether file is the JSON or HTML page stem, the two
+// formats DYI exports come in.
+func matchData(file, stem string) bool {
+	return matchBase(file, stem+".json") || matchBase(file, stem+".html")
+}
+
+// mediaItem maps a media reference; caption and takenAt are the post's and
+// apply unless the file carries its own.
+func mediaItem(media rawMedia, caption string, takenAt int64, username string) instagram.MediaItem {
+	if caption == "" {
+		caption = media.Title
+	}
+	if media.CreationTimestamp > 0 {
+		takenAt = media.CreationTimestamp
+	}
+	item := instagram.MediaItem{
+		URL:      strings.TrimSpace(media.URI),
+		TakenAt:  takenAt,
+		Username: username,
+		Caption:  strings.TrimSpace(FixText(caption)),
+	}
+	if isVideoURI(item.URL) {
+		item.IsVideo = true
+		item.VideoURL = item.URL
+	}
+	return item
+}
+
+func isVideoURI(uri string) bool {
+	switch strings.ToLower(path.Ext(uri)) {
+	case ".mp4", ".mov", ".webm":
+		return true
+	}
+	return false
+}
+
+// decodeList decodes a JSON array file into v. DYI wraps most lists in an
+// object with a single key ("ig_stories", "relationships_following", ...)
+// whose name changes between export versions, so for objects the first array
+// field is used.
+func (e *Export) decodeList(name string, v any) error {
+	if path.Ext(name) == ".html" {
+		return e.decodeHTML(name, v)
+	}
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		keys := make([]string, 0, len(wrapper))
+		for key := range wrapper {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		data = []byte("[]")
+		for _, key := range keys {
+			if value := strings.TrimSpace(string(wrapper[key])); strings.HasPrefix(value, "[") {
+				data = wrapper[key]
+				break
+			}
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
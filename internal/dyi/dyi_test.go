@@ -0,0 +1,84 @@
+package dyi
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeZip builds a test export from name → content pairs.
+func writeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip entry %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("zip write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("file close: %v", err)
+	}
+	return path
+}
+
+func TestFixText(t *testing.T) {
+	cases := map[string]string{
+		"":                    "",
+		"plain":               "plain",
+		"cafÃ©":               "café",
+		"ð\u009f\u0098\u0082": "😂",
+		"already café":        "already café",
+		"Ã alone":             "Ã alone",
+	}
+	for input, want := range cases {
+		if got := FixText(input); got != want {
+			t.Fatalf("FixText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMatchBase(t *testing.T) {
+	cases := map[string]bool{
+		"content/posts_1.json":        true,
+		"a/b/posts.json":              true,
+		"content/archived_posts.json": false,
+		"content/posts_x.json":        false,
+		"content/posts_.json":         false,
+	}
+	for file, want := range cases {
+		if got := matchBase(file, "posts.json"); got != want {
+			t.Fatalf("matchBase(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestReadFileResolvesNestedRoot(t *testing.T) {
+	path := writeZip(t, map[string]string{
+		"instagram-me-2024/media/posts/1.jpg": "jpeg",
+	})
+	export, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+	data, err := export.ReadFile("media/posts/1.jpg")
+	if err != nil || string(data) != "jpeg" {
+		t.Fatalf("expected nested file, got %q (%v)", data, err)
+	}
+	if _, err := export.ReadFile("media/posts/missing.jpg"); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+}
@@ -0,0 +1,305 @@
+package dyi
+
+import (
+	"path"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// Facebook is everything metcli reads from a Facebook DYI export. Media URLs
+// are paths relative to the export root; read them with ReadFile.
+type Facebook struct {
+	Name      string
+	Posts     []FacebookPost
+	Albums    []Album
+	Photos    []instagram.MediaItem
+	Comments  []FacebookComment
+	Reactions []FacebookReaction
+	Friends   []Friend
+	Threads   []Conversation
+	Warnings  []string
+}
+
+// FacebookPost is a timeline post with its attached photos, videos and links.
+type FacebookPost struct {
+	Timestamp int64
+	Title     string
+	Text      string
+	Media     []instagram.MediaItem
+	Links     []string
+}
+
+// Album is a named photo album.
+type Album struct {
+	Name        string
+	Description string
+	UpdatedAt   int64
+	Photos      []instagram.MediaItem
+}
+
+// FacebookComment is a comment the account wrote. Title describes where, as
+// in "Jane commented on John's post."
+type FacebookComment struct {
+	Timestamp int64
+	Author    string
+	Text      string
+	Title     string
+}
+
+// FacebookReaction is a like or reaction the account gave.
+type FacebookReaction struct {
+	Timestamp int64
+	Actor     string
+	Reaction  string
+	Title     string
+}
+
+// Friend is a Facebook friend and when the friendship started.
+type Friend struct {
+	Name  string
+	Since int64
+}
+
+type fbPost struct {
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title"`
+	Data      []struct {
+		Post string `json:"post"`
+	} `json:"data"`
+	Attachments []struct {
+		Data []struct {
+			Media           *rawMedia `json:"media"`
+			ExternalContext *struct {
+				URL string `json:"url"`
+			} `json:"external_context"`
+		} `json:"data"`
+	} `json:"attachments"`
+}
+
+type fbAlbum struct {
+	Name                  string     `json:"name"`
+	Description           string     `json:"description"`
+	LastModifiedTimestamp int64      `json:"last_modified_timestamp"`
+	Photos                []rawMedia `json:"photos"`
+}
+
+type fbComment struct {
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title"`
+	Data      []struct {
+		Comment struct {
+			Timestamp int64  `json:"timestamp"`
+			Comment   string `json:"comment"`
+			Author    string `json:"author"`
+		} `json:"comment"`
+	} `json:"data"`
+}
+
+type fbReaction struct {
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title"`
+	Data      []struct {
+		Reaction struct {
+			Reaction string `json:"reaction"`
+			Actor    string `json:"actor"`
+		} `json:"reaction"`
+	} `json:"data"`
+}
+
+type fbFriend struct {
+	Name      string `json:"name"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type fbProfile struct {
+	Profile struct {
+		Name struct {
+			FullName string `json:"full_name"`
+		} `json:"name"`
+	} `json:"profile_v2"`
+}
+
+// Facebook parses the Facebook data of the export. Missing sections are left
+// empty; unreadable ones are reported in Warnings.
+func (e *Export) Facebook() Facebook {
+	var out Facebook
+	warn := func(err error) {
+		if err != nil {
+			out.Warnings = append(out.Warnings, err.Error())
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool { return matchBase(f, "profile_information.json") }) {
+		var profile fbProfile
+		if err := e.decode(name, &profile); err != nil {
+			warn(err)
+			continue
+		}
+		out.Name = FixText(strings.TrimSpace(profile.Profile.Name.FullName))
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return matchBase(f, "your_posts__check_ins__photos_and_videos.json") || matchBase(f, "your_posts.json")
+	}) {
+		var posts []fbPost
+		if err := e.decodeList(name, &posts); err != nil {
+			warn(err)
+			continue
+		}
+		for _, post := range posts {
+			out.Posts = append(out.Posts, toFacebookPost(post, out.Name))
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return strings.Contains("/"+f, "/album/") && path.Ext(f) == ".json"
+	}) {
+		var album fbAlbum
+		if err := e.decode(name, &album); err != nil {
+			warn(err)
+			continue
+		}
+		converted := Album{
+			Name:        FixText(strings.TrimSpace(album.Name)),
+			Description: FixText(strings.TrimSpace(album.Description)),
+			UpdatedAt:   album.LastModifiedTimestamp,
+		}
+		for _, photo := range album.Photos {
+			converted.Photos = append(converted.Photos, facebookMedia(photo, "", 0, out.Name))
+		}
+		out.Albums = append(out.Albums, converted)
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return matchBase(f, "your_uncategorized_photos.json") || matchBase(f, "your_videos.json")
+	}) {
+		var media []rawMedia
+		if err := e.decodeList(name, &media); err != nil {
+			warn(err)
+			continue
+		}
+		for _, item := range media {
+			out.Photos = append(out.Photos, facebookMedia(item, "", 0, out.Name))
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool { return matchBase(f, "comments.json") }) {
+		var comments []fbComment
+		if err := e.decodeList(name, &comments); err != nil {
+			warn(err)
+			continue
+		}
+		for _, comment := range comments {
+			for _, data := range comment.Data {
+				timestamp := data.Comment.Timestamp
+				if timestamp == 0 {
+					timestamp = comment.Timestamp
+				}
+				out.Comments = append(out.Comments, FacebookComment{
+					Timestamp: timestamp,
+					Author:    FixText(strings.TrimSpace(data.Comment.Author)),
+					Text:      FixText(data.Comment.Comment),
+					Title:     FixText(strings.TrimSpace(comment.Title)),
+				})
+			}
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return matchBase(f, "likes_and_reactions.json") || matchBase(f, "posts_and_comments.json")
+	}) {
+		var reactions []fbReaction
+		if err := e.decodeList(name, &reactions); err != nil {
+			warn(err)
+			continue
+		}
+		for _, reaction := range reactions {
+			for _, data := range reaction.Data {
+				out.Reactions = append(out.Reactions, FacebookReaction{
+					Timestamp: reaction.Timestamp,
+					Actor:     FixText(strings.TrimSpace(data.Reaction.Actor)),
+					Reaction:  FixText(strings.TrimSpace(data.Reaction.Reaction)),
+					Title:     FixText(strings.TrimSpace(reaction.Title)),
+				})
+			}
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return matchBase(f, "your_friends.json") || matchBase(f, "friends.json")
+	}) {
+		var friends []fbFriend
+		if err := e.decodeList(name, &friends); err != nil {
+			warn(err)
+			continue
+		}
+		for _, friend := range friends {
+			out.Friends = append(out.Friends, Friend{
+				Name:  FixText(strings.TrimSpace(friend.Name)),
+				Since: friend.Timestamp,
+			})
+		}
+	}
+
+	threads, warnings := e.conversations("/messages/")
+	out.Threads = threads
+	out.Warnings = append(out.Warnings, warnings...)
+	return out
+}
+
+// Media lists every photo and video of the export: post attachments, albums
+// and uncategorized uploads, without repeats.
+func (f Facebook) Media() []instagram.MediaItem {
+	var out []instagram.MediaItem
+	seen := map[string]struct{}{}
+	add := func(items []instagram.MediaItem) {
+		for _, item := range items {
+			if _, ok := seen[item.URL]; ok || item.URL == "" {
+				continue
+			}
+			seen[item.URL] = struct{}{}
+			out = append(out, item)
+		}
+	}
+	for _, post := range f.Posts {
+		add(post.Media)
+	}
+	for _, album := range f.Albums {
+		add(album.Photos)
+	}
+	add(f.Photos)
+	return out
+}
+
+func toFacebookPost(raw fbPost, owner string) FacebookPost {
+	post := FacebookPost{
+		Timestamp: raw.Timestamp,
+		Title:     FixText(strings.TrimSpace(raw.Title)),
+	}
+	for _, data := range raw.Data {
+		if text := strings.TrimSpace(data.Post); text != "" {
+			post.Text = FixText(text)
+		}
+	}
+	for _, attachment := range raw.Attachments {
+		for _, data := range attachment.Data {
+			if data.Media != nil {
+				post.Media = append(post.Media, facebookMedia(*data.Media, post.Text, raw.Timestamp, owner))
+			}
+			if data.ExternalContext != nil && strings.TrimSpace(data.ExternalContext.URL) != "" {
+				post.Links = append(post.Links, strings.TrimSpace(data.ExternalContext.URL))
+			}
+		}
+	}
+	return post
+}
+
+// facebookMedia prefers the description Facebook stores on a photo over its
+// title, which is usually the album name.
+func facebookMedia(media rawMedia, caption string, takenAt int64, owner string) instagram.MediaItem {
+	if description := strings.TrimSpace(media.Description); description != "" {
+		caption = description
+	}
+	return mediaItem(media, caption, takenAt, owner)
+}
@@ -0,0 +1,71 @@
+package dyi
+
+import "testing"
+
+func TestFacebookExport(t *testing.T) {
+	path := writeZip(t, map[string]string{
+		"profile_information/profile_information.json": `{"profile_v2": {"name": {"full_name": "JÃ¼rgen"}}}`,
+		"your_facebook_activity/posts/your_posts__check_ins__photos_and_videos_1.json": `[
+			{"timestamp": 100, "title": "Jürgen updated his status.",
+			 "data": [{"post": "hello"}, {"update_timestamp": 101}],
+			 "attachments": [{"data": [
+				{"media": {"uri": "your_facebook_activity/posts/media/p/1.jpg", "creation_timestamp": 99, "description": "sunset"}},
+				{"external_context": {"url": "https://example.com"}}]}]}
+		]`,
+		"your_facebook_activity/posts/album/0.json": `{"name": "Trip", "last_modified_timestamp": 200,
+			"photos": [{"uri": "your_facebook_activity/posts/media/Trip/2.jpg", "creation_timestamp": 150, "title": "Trip"},
+			           {"uri": "your_facebook_activity/posts/media/p/1.jpg"}]}`,
+		"your_facebook_activity/posts/your_videos.json": `{"videos_v2": [{"uri": "your_facebook_activity/posts/media/v/3.mp4", "creation_timestamp": 160}]}`,
+		"your_facebook_activity/comments_and_reactions/comments.json": `{"comments_v2": [
+			{"timestamp": 300, "title": "Jürgen commented on Ann's post.",
+			 "data": [{"comment": {"timestamp": 301, "comment": "nice", "author": "Jürgen"}}]}]}`,
+		"your_facebook_activity/comments_and_reactions/likes_and_reactions_1.json": `[
+			{"timestamp": 400, "title": "Jürgen liked Ann's post.", "data": [{"reaction": {"reaction": "LIKE", "actor": "Jürgen"}}]}]`,
+		"connections/friends/your_friends.json": `{"friends_v2": [{"name": "Ann", "timestamp": 50}]}`,
+		"your_facebook_activity/messages/inbox/ann_1/message_1.json": `{
+			"participants": [{"name": "Ann"}, {"name": "Jürgen"}], "title": "Ann",
+			"thread_path": "inbox/ann_1",
+			"messages": [{"sender_name": "Ann", "timestamp_ms": 5000, "content": "hey"}]}`,
+	})
+	export, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+
+	data := export.Facebook()
+	if len(data.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", data.Warnings)
+	}
+	if data.Name != "Jürgen" {
+		t.Fatalf("expected fixed profile name, got %q", data.Name)
+	}
+	if len(data.Posts) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(data.Posts))
+	}
+	post := data.Posts[0]
+	if post.Text != "hello" || len(post.Media) != 1 || post.Media[0].Caption != "sunset" || len(post.Links) != 1 {
+		t.Fatalf("unexpected post: %+v", post)
+	}
+	if len(data.Albums) != 1 || data.Albums[0].Name != "Trip" || len(data.Albums[0].Photos) != 2 {
+		t.Fatalf("unexpected albums: %+v", data.Albums)
+	}
+	if len(data.Photos) != 1 || !data.Photos[0].IsVideo {
+		t.Fatalf("unexpected photos: %+v", data.Photos)
+	}
+	if media := data.Media(); len(media) != 3 {
+		t.Fatalf("expected 3 distinct media files, got %d", len(media))
+	}
+	if len(data.Comments) != 1 || data.Comments[0].Timestamp != 301 || data.Comments[0].Text != "nice" {
+		t.Fatalf("unexpected comments: %+v", data.Comments)
+	}
+	if len(data.Reactions) != 1 || data.Reactions[0].Reaction != "LIKE" {
+		t.Fatalf("unexpected reactions: %+v", data.Reactions)
+	}
+	if len(data.Friends) != 1 || data.Friends[0].Name != "Ann" || data.Friends[0].Since != 50 {
+		t.Fatalf("unexpected friends: %+v", data.Friends)
+	}
+	if len(data.Threads) != 1 || len(data.Threads[0].Messages) != 1 {
+		t.Fatalf("unexpected threads: %+v", data.Threads)
+	}
+}
@@ -0,0 +1,306 @@
+package dyi
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// htmlBlock is one entry of a DYI HTML page. Meta renders every post,
+// account, comment and message as its own "uiBoxWhite" box carrying the
+// same fields as the JSON export, only as text.
+type htmlBlock struct {
+	texts []string
+	rows  [][]string // table rows, for label/value entries
+	links []string   // absolute hrefs
+	media []string   // export-relative hrefs and srcs
+	items []string   // list item texts (message reactions)
+}
+
+type htmlPage struct {
+	title  string
+	blocks []htmlBlock
+}
+
+var (
+	htmlTagPattern  = regexp.MustCompile(`(?is)<!--.*?-->|<(/?)([a-z][a-z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	htmlAttrPattern = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+var htmlTimeLayouts = []string{
+	"Jan 2, 2006 3:04 pm",
+	"Jan 2, 2006, 3:04 pm",
+	"Jan 2, 2006 3:04:05 pm",
+	"Jan 2, 2006, 3:04:05 pm",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// parseHTMLPage splits a DYI HTML page into its entries. Meta's pages are
+// machine generated and regular enough that a tag scanner suffices.
+func parseHTMLPage(data string) htmlPage {
+	var page htmlPage
+	var block *htmlBlock
+	skip, inTitle, inRow, inItem := false, false, false, 0
+	text := func(raw string) {
+		s := strings.Join(strings.Fields(html.UnescapeString(raw)), " ")
+		switch {
+		case s == "" || skip:
+		case inTitle:
+			page.title += s
+		case block == nil:
+		case inItem > 0:
+			block.items = append(block.items, s)
+		default:
+			block.texts = append(block.texts, s)
+			if inRow {
+				row := &block.rows[len(block.rows)-1]
+				*row = append(*row, s)
+			}
+		}
+	}
+	last := 0
+	for _, m := range htmlTagPattern.FindAllStringSubmatchIndex(data, -1) {
+		text(data[last:m[0]])
+		last = m[1]
+		if m[4] < 0 { // comment
+			continue
+		}
+		closing := m[3] > m[2]
+		attrs := htmlAttrs(data[m[6]:m[7]])
+		switch strings.ToLower(data[m[4]:m[5]]) {
+		case "script", "style":
+			skip = !closing
+		case "title":
+			inTitle = !closing
+		case "li":
+			if !closing {
+				inItem++
+			} else if inItem > 0 {
+				inItem--
+			}
+		case "tr":
+			inRow = !closing && block != nil
+			if inRow {
+				block.rows = append(block.rows, nil)
+			}
+		case "div":
+			if !closing && strings.Contains(" "+attrs["class"]+" ", " uiBoxWhite ") {
+				page.blocks = append(page.blocks, htmlBlock{})
+				block = &page.blocks[len(page.blocks)-1]
+				inRow, inItem = false, 0
+			}
+		}
+		if block != nil && !closing {
+			block.ref(attrs["href"])
+			block.ref(attrs["src"])
+		}
+	}
+	text(data[last:])
+	return page
+}
+
+func htmlAttrs(s string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range htmlAttrPattern.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3])
+	}
+	return attrs
+}
+
+// ref files an href or src as a link or, for paths into the export, as
+// media. Thumbnails repeat their link target, so media is deduplicated.
+func (b *htmlBlock) ref(ref string) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "data:"):
+	case strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://"):
+		b.links = append(b.links, ref)
+	case !strings.Contains(ref, ":"):
+		for _, seen := range b.media {
+			if seen == ref {
+				return
+			}
+		}
+		b.media = append(b.media, ref)
+	}
+}
+
+// date returns the entry's timestamp, which DYI prints last, and its index
+// in texts, or -1.
+func (b htmlBlock) date() (int64, int) {
+	for i := len(b.texts) - 1; i >= 0; i-- {
+		if ts := htmlTime(b.texts[i]); ts > 0 {
+			return ts, i
+		}
+	}
+	return 0, -1
+}
+
+// content returns the texts other than the date and link labels, which
+// repeat the URL.
+func (b htmlBlock) content() []string {
+	_, skip := b.date()
+	var out []string
+	for i, text := range b.texts {
+		if i == skip || b.isRef(text) {
+			continue
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+func (b htmlBlock) isRef(text string) bool {
+	for _, ref := range append(append([]string{}, b.links...), b.media...) {
+		if text == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlTime parses the dates DYI HTML pages print in the account's local
+// time, such as "Jan 02, 2024 3:04 pm" or "Jan 2, 2024, 3:04:05 PM".
+func htmlTime(s string) int64 {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	for _, layout := range htmlTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t.Unix()
+		}
+	}
+	return 0
+}
+
+// decodeHTML reads an HTML page into the value its JSON counterpart decodes
+// to, so both formats share one mapping to the Instagram model.
+func (e *Export) decodeHTML(name string, v any) error {
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+	page := parseHTMLPage(string(data))
+	switch v := v.(type) {
+	case *[]igPost:
+		for _, block := range page.blocks {
+			if post, ok := htmlPost(block); ok {
+				*v = append(*v, post)
+			}
+		}
+	case *[]rawMedia:
+		for _, block := range page.blocks {
+			if post, ok := htmlPost(block); ok {
+				for _, media := range post.Media {
+					media.Title = post.Title
+					media.CreationTimestamp = post.CreationTimestamp
+					*v = append(*v, media)
+				}
+			}
+		}
+	case *[]igStringEntry:
+		for _, block := range page.blocks {
+			var entry igStringEntry
+			if texts := block.content(); len(texts) > 0 {
+				entry.Title = texts[0]
+			}
+			var href string
+			if len(block.links) > 0 {
+				href = block.links[0]
+			}
+			if entry.Title == "" && href == "" {
+				continue
+			}
+			entry.StringListData = append(entry.StringListData, igStringData{Href: href, Timestamp: firstDate(block)})
+			*v = append(*v, entry)
+		}
+	case *[]igStringMapEntry:
+		for _, block := range page.blocks {
+			entry := igStringMapEntry{StringMapData: map[string]igStringData{}}
+			for _, row := range block.rows {
+				if len(row) < 2 {
+					continue
+				}
+				value := strings.Join(row[1:], "\n")
+				entry.StringMapData[row[0]] = igStringData{Value: value, Timestamp: htmlTime(value)}
+			}
+			if len(entry.StringMapData) > 0 {
+				*v = append(*v, entry)
+			}
+		}
+	case *rawThread:
+		*v = htmlThread(page)
+	default:
+		return fmt.Errorf("%s: unsupported HTML page", name)
+	}
+	return nil
+}
+
+func firstDate(block htmlBlock) int64 {
+	ts, _ := block.date()
+	return ts
+}
+
+func htmlPost(block htmlBlock) (igPost, bool) {
+	if len(block.media) == 0 {
+		return igPost{}, false
+	}
+	post := igPost{CreationTimestamp: firstDate(block)}
+	if texts := block.content(); len(texts) > 0 {
+		post.Title = texts[0]
+	}
+	for _, uri := range block.media {
+		post.Media = append(post.Media, rawMedia{URI: uri})
+	}
+	return post, true
+}
+
+// htmlThread maps a message_N.html page. The page names no participants,
+// so they are taken from the senders; each entry is the sender, the text
+// and the date.
+func htmlThread(page htmlPage) rawThread {
+	thread := rawThread{Title: page.title}
+	senders := map[string]bool{}
+	for _, block := range page.blocks {
+		texts := block.content()
+		if len(texts) == 0 {
+			continue
+		}
+		ts, _ := block.date()
+		msg := rawMessage{
+			SenderName:  texts[0],
+			TimestampMS: ts * 1000,
+			Content:     strings.Join(texts[1:], "\n"),
+		}
+		for _, uri := range block.media {
+			if isVideoURI(uri) {
+				msg.Videos = append(msg.Videos, rawMedia{URI: uri})
+			} else {
+				msg.Photos = append(msg.Photos, rawMedia{URI: uri})
+			}
+		}
+		if len(block.links) > 0 {
+			msg.Share = &rawShare{Link: block.links[0]}
+		}
+		for _, item := range block.items {
+			// Reactions read "❤Name": the emoji runs up to the first letter.
+			split := strings.IndexFunc(item, unicode.IsLetter)
+			if split <= 0 {
+				continue
+			}
+			msg.Reactions = append(msg.Reactions, rawReaction{
+				Reaction: strings.TrimSpace(item[:split]),
+				Actor:    strings.TrimSpace(item[split:]),
+			})
+		}
+		if !senders[msg.SenderName] {
+			senders[msg.SenderName] = true
+			thread.Participants = append(thread.Participants, rawParticipant{Name: msg.SenderName})
+		}
+		thread.Messages = append(thread.Messages, msg)
+	}
+	return thread
+}
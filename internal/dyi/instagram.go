@@ -0,0 +1,223 @@
+package dyi
+
+import (
+	"path"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// Instagram is everything metcli reads from an Instagram DYI export, in JSON
+// or HTML format. Media URLs are paths relative to the export root; read
+// them with ReadFile.
+type Instagram struct {
+	Username  string
+	Posts     []instagram.MediaItem
+	Stories   []instagram.MediaItem
+	Reels     []instagram.MediaItem
+	Liked     []instagram.MediaItem
+	Comments  []instagram.Comment
+	Followers []instagram.Account
+	Following []instagram.Account
+	Threads   []Conversation
+	Warnings  []string
+}
+
+type igPost struct {
+	Media             []rawMedia `json:"media"`
+	Title             string     `json:"title"`
+	CreationTimestamp int64      `json:"creation_timestamp"`
+}
+
+type igStringData struct {
+	Href      string `json:"href"`
+	Value     string `json:"value"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type igStringEntry struct {
+	Title          string         `json:"title"`
+	StringListData []igStringData `json:"string_list_data"`
+}
+
+type igStringMapEntry struct {
+	StringMapData map[string]igStringData `json:"string_map_data"`
+}
+
+// Instagram parses the Instagram data of the export. Missing sections are
+// left empty; unreadable ones are reported in Warnings.
+func (e *Export) Instagram() Instagram {
+	var out Instagram
+	warn := func(err error) {
+		if err != nil {
+			out.Warnings = append(out.Warnings, err.Error())
+		}
+	}
+
+	out.Username = e.instagramUsername()
+
+	for _, name := range e.find(func(f string) bool { return matchData(f, "posts") }) {
+		var posts []igPost
+		if err := e.decodeList(name, &posts); err != nil {
+			warn(err)
+			continue
+		}
+		for _, post := range posts {
+			out.Posts = append(out.Posts, postMedia(post, out.Username)...)
+		}
+	}
+	for _, name := range e.find(func(f string) bool { return matchData(f, "reels") }) {
+		var reels []igPost
+		if err := e.decodeList(name, &reels); err != nil {
+			warn(err)
+			continue
+		}
+		for _, reel := range reels {
+			out.Reels = append(out.Reels, postMedia(reel, out.Username)...)
+		}
+	}
+	for _, name := range e.find(func(f string) bool { return matchData(f, "stories") }) {
+		var stories []rawMedia
+		if err := e.decodeList(name, &stories); err != nil {
+			warn(err)
+			continue
+		}
+		for _, story := range stories {
+			out.Stories = append(out.Stories, mediaItem(story, "", 0, out.Username))
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool { return matchData(f, "liked_posts") }) {
+		var likes []igStringEntry
+		if err := e.decodeList(name, &likes); err != nil {
+			warn(err)
+			continue
+		}
+		for _, like := range likes {
+			for _, data := range like.StringListData {
+				out.Liked = append(out.Liked, instagram.MediaItem{
+					Shortcode: instagram.ParseShortcode(data.Href),
+					Username:  FixText(strings.TrimSpace(like.Title)),
+					TakenAt:   data.Timestamp,
+				})
+			}
+		}
+	}
+
+	for _, name := range e.find(func(f string) bool {
+		return matchData(f, "post_comments") || matchData(f, "reels_comments")
+	}) {
+		var comments []igStringMapEntry
+		if err := e.decodeList(name, &comments); err != nil {
+			warn(err)
+			continue
+		}
+		for _, comment := range comments {
+			data := comment.StringMapData
+			out.Comments = append(out.Comments, instagram.Comment{
+				Username:   out.Username,
+				Text:       FixText(data["Comment"].Value),
+				CreatedAt:  data["Time"].Timestamp,
+				MediaOwner: FixText(data["Media Owner"].Value),
+			})
+		}
+	}
+
+	followers, err := e.accounts("followers")
+	warn(err)
+	out.Followers = followers
+	following, err := e.accounts("following")
+	warn(err)
+	out.Following = following
+
+	threads, warnings := e.conversations("/messages/inbox/")
+	out.Threads = threads
+	out.Warnings = append(out.Warnings, warnings...)
+
+	if len(out.Posts)+len(out.Stories)+len(out.Reels) == 0 {
+		out.Posts, out.Stories, out.Reels = e.htmlMedia(out.Username)
+		if len(out.Posts)+len(out.Stories)+len(out.Reels) > 0 {
+			out.Warnings = append(out.Warnings,
+				"no post data found in the export pages; only media files were read, without captions or dates")
+		}
+	}
+	return out
+}
+
+func (e *Export) instagramUsername() string {
+	names := e.find(func(f string) bool { return matchData(f, "personal_information") })
+	for _, name := range names {
+		var info []igStringMapEntry
+		if err := e.decodeList(name, &info); err != nil {
+			continue
+		}
+		for _, entry := range info {
+			if username := strings.TrimSpace(entry.StringMapData["Username"].Value); username != "" {
+				return FixText(username)
+			}
+		}
+	}
+	return ""
+}
+
+func (e *Export) accounts(stem string) ([]instagram.Account, error) {
+	var out []instagram.Account
+	seen := map[string]struct{}{}
+	for _, name := range e.find(func(f string) bool { return matchData(f, stem) }) {
+		var entries []igStringEntry
+		if err := e.decodeList(name, &entries); err != nil {
+			return out, err
+		}
+		for _, entry := range entries {
+			for _, data := range entry.StringListData {
+				// Newer exports move the username from value to title.
+				username := strings.TrimSpace(data.Value)
+				if username == "" {
+					username = strings.TrimSpace(entry.Title)
+				}
+				if username == "" {
+					username = instagram.ParseUsername(strings.Replace(data.Href, "/_u/", "/", 1))
+				}
+				if _, ok := seen[username]; ok || username == "" {
+					continue
+				}
+				seen[username] = struct{}{}
+				out = append(out, instagram.Account{Username: FixText(username)})
+			}
+		}
+	}
+	return out, nil
+}
+
+// htmlMedia lists the media files of an export whose pages yielded no posts,
+// such as an HTML layout the page scanner does not recognise.
+func (e *Export) htmlMedia(username string) (posts, stories, reels []instagram.MediaItem) {
+	for _, file := range e.files {
+		ext := strings.ToLower(path.Ext(file))
+		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".webp" && ext != ".heic" && !isVideoURI(file) {
+			continue
+		}
+		item := mediaItem(rawMedia{URI: file}, "", 0, username)
+		switch {
+		case strings.Contains("/"+file, "/media/posts/"):
+			posts = append(posts, item)
+		case strings.Contains("/"+file, "/media/stories/"):
+			stories = append(stories, item)
+		case strings.Contains("/"+file, "/media/reels/"):
+			reels = append(reels, item)
+		}
+	}
+	return posts, stories, reels
+}
+
+func postMedia(post igPost, username string) []instagram.MediaItem {
+	caption := post.Title
+	if caption == "" && len(post.Media) > 0 {
+		caption = post.Media[0].Title
+	}
+	out := make([]instagram.MediaItem, 0, len(post.Media))
+	for _, media := range post.Media {
+		out = append(out, mediaItem(media, caption, post.CreationTimestamp, username))
+	}
+	return out
+}
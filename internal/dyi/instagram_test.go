@@ -0,0 +1,207 @@
+package dyi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstagramExport(t *testing.T) {
+	path := writeZip(t, map[string]string{
+		"personal_information/personal_information/personal_information.json": `{"profile_user": [
+			{"string_map_data": {"Username": {"value": "me"}}}]}`,
+		"your_instagram_activity/content/posts_1.json": `[
+			{"media": [{"uri": "media/posts/202401/a.jpg", "creation_timestamp": 20, "title": "cafÃ©"}]},
+			{"title": "carousel", "creation_timestamp": 10, "media": [
+				{"uri": "media/posts/202401/b.jpg", "creation_timestamp": 10},
+				{"uri": "media/posts/202401/c.mp4", "creation_timestamp": 10}]}
+		]`,
+		"your_instagram_activity/content/stories.json": `{"ig_stories": [
+			{"uri": "media/stories/202401/s.jpg", "creation_timestamp": 30}]}`,
+		"your_instagram_activity/likes/liked_posts.json": `{"likes_media_likes": [
+			{"title": "bob", "string_list_data": [{"href": "https://www.instagram.com/p/LIKED/", "timestamp": 5}]}]}`,
+		"your_instagram_activity/comments/post_comments_1.json": `[
+			{"string_map_data": {"Comment": {"value": "nice"}, "Media Owner": {"value": "bob"}, "Time": {"timestamp": 6}}}]`,
+		"connections/followers_and_following/followers_1.json": `[
+			{"string_list_data": [{"href": "https://www.instagram.com/alice", "value": "alice", "timestamp": 1}]}]`,
+		"connections/followers_and_following/following.json": `{"relationships_following": [
+			{"title": "carol", "string_list_data": [{"href": "https://www.instagram.com/_u/carol", "timestamp": 2}]}]}`,
+		"your_instagram_activity/messages/inbox/alice_1/message_1.json": `{
+			"participants": [{"name": "Alice"}, {"name": "Me"}],
+			"title": "Alice", "thread_path": "inbox/alice_1",
+			"messages": [
+				{"sender_name": "Alice", "timestamp_ms": 2000, "content": "hi ð\u009f\u0098\u0082",
+				 "reactions": [{"reaction": "â\u009d¤", "actor": "Me"}]},
+				{"sender_name": "Me", "timestamp_ms": 1000, "photos": [{"uri": "media/x.jpg"}]}
+			]}`,
+	})
+	export, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+
+	data := export.Instagram()
+	if len(data.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", data.Warnings)
+	}
+	if data.Username != "me" {
+		t.Fatalf("expected owner username, got %q", data.Username)
+	}
+	if len(data.Posts) != 3 {
+		t.Fatalf("expected 3 post files, got %d", len(data.Posts))
+	}
+	if data.Posts[0].Caption != "café" || data.Posts[0].TakenAt != 20 || data.Posts[0].Username != "me" {
+		t.Fatalf("unexpected first post: %+v", data.Posts[0])
+	}
+	if data.Posts[1].Caption != "carousel" || !data.Posts[2].IsVideo || data.Posts[2].VideoURL == "" {
+		t.Fatalf("unexpected carousel: %+v / %+v", data.Posts[1], data.Posts[2])
+	}
+	if len(data.Stories) != 1 || data.Stories[0].TakenAt != 30 {
+		t.Fatalf("unexpected stories: %+v", data.Stories)
+	}
+	if len(data.Liked) != 1 || data.Liked[0].Shortcode != "LIKED" || data.Liked[0].Username != "bob" {
+		t.Fatalf("unexpected likes: %+v", data.Liked)
+	}
+	if len(data.Comments) != 1 || data.Comments[0].MediaOwner != "bob" || data.Comments[0].Text != "nice" {
+		t.Fatalf("unexpected comments: %+v", data.Comments)
+	}
+	if len(data.Followers) != 1 || data.Followers[0].Username != "alice" {
+		t.Fatalf("unexpected followers: %+v", data.Followers)
+	}
+	if len(data.Following) != 1 || data.Following[0].Username != "carol" {
+		t.Fatalf("unexpected following: %+v", data.Following)
+	}
+	if len(data.Threads) != 1 {
+		t.Fatalf("expected 1 thread, got %d", len(data.Threads))
+	}
+	conv := data.Threads[0]
+	if conv.Thread.LastActivityAt != 2 || len(conv.Messages) != 2 {
+		t.Fatalf("unexpected thread: %+v", conv.Thread)
+	}
+	if conv.Messages[0].Type != "media" || conv.Messages[1].Text != "hi 😂" {
+		t.Fatalf("expected chronological, fixed messages: %+v", conv.Messages)
+	}
+	if conv.Messages[1].Reactions[0].Emoji != "❤" {
+		t.Fatalf("unexpected reaction: %+v", conv.Messages[1].Reactions)
+	}
+}
+
+func TestInstagramHTMLExportFallsBackToMedia(t *testing.T) {
+	path := writeZip(t, map[string]string{
+		"your_instagram_activity/content/posts_1.html": "<html></html>",
+		"media/posts/202401/a.jpg":                     "jpeg",
+		"media/stories/202401/b.mp4":                   "mp4",
+	})
+	export, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+
+	data := export.Instagram()
+	if len(data.Posts) != 1 || len(data.Stories) != 1 || !data.Stories[0].IsVideo {
+		t.Fatalf("unexpected media: %+v / %+v", data.Posts, data.Stories)
+	}
+	if len(data.Warnings) != 1 {
+		t.Fatalf("expected HTML warning, got %v", data.Warnings)
+	}
+}
+
+func TestInstagramHTMLExport(t *testing.T) {
+	const box = `<div class="pam _3-95 _2ph- _a6-g uiBoxWhite noborder">`
+	path := writeZip(t, map[string]string{
+		"personal_information/personal_information/personal_information.html": `<html><body>` + box +
+			`<table><tr><td class="_2pin">Username<div><div>me</div></div></td></tr>` +
+			`<tr><td class="_2pin">Bio</td></tr></table></div></body></html>`,
+		"your_instagram_activity/content/posts_1.html": `<html><head><title>Posts</title>` +
+			`<style>.x{}</style></head><body><div class="_a706">` +
+			box + `<h2 class="_3-95 _a6-h">caf&eacute; &amp; more #tag</h2>` +
+			`<div><a href="media/posts/202401/a.jpg"><img src="media/posts/202401/a.jpg" class="_a6_o"/></a>` +
+			`<div><video src="media/posts/202401/b.mp4"></video></div></div>` +
+			`<div class="_3-94 _a6-o">Jan 02, 2024 3:04 pm</div></div>` +
+			box + `<div><a href="media/posts/202401/c.jpg"><img src="media/posts/202401/c.jpg"/></a></div>` +
+			`<div class="_3-94 _a6-o">Jan 1, 2024, 9:00:00` + " " + `AM</div></div>` +
+			`</div></body></html>`,
+		"your_instagram_activity/likes/liked_posts.html": `<html><body>` + box +
+			`<h2>bob</h2><div><div><a target="_blank" href="https://www.instagram.com/p/LIKED/">` +
+			`https://www.instagram.com/p/LIKED/</a></div><div>Jan 03, 2024 10:00 am</div></div></div></body></html>`,
+		"your_instagram_activity/comments/post_comments_1.html": `<html><body>` + box +
+			`<table><tr><td>Comment<div><div>nice</div></div></td></tr>` +
+			`<tr><td>Media Owner<div><div>bob</div></div></td></tr>` +
+			`<tr><td>Time<div><div>Jan 04, 2024 11:00 am</div></div></td></tr></table></div></body></html>`,
+		"connections/followers_and_following/followers_1.html": `<html><body>` + box +
+			`<div><div><a target="_blank" href="https://www.instagram.com/alice">alice</a></div>` +
+			`<div>Jan 05, 2024 1:00 pm</div></div></div></body></html>`,
+		"connections/followers_and_following/following.html": `<html><body>` + box +
+			`<h2>carol</h2><div><div><a href="https://www.instagram.com/_u/carol">https://www.instagram.com/_u/carol</a></div>` +
+			`<div>Jan 06, 2024 1:00 pm</div></div></div></body></html>`,
+		"your_instagram_activity/messages/inbox/alice_1/message_1.html": `<html><head><title>Alice</title></head><body>` +
+			box + `<h2>Alice</h2><div><div><div></div><div>hi &#x1F602;</div><div></div>` +
+			`<ul><li><span>` + "❤" + `Me</span></li></ul></div></div>` +
+			`<div class="_3-94 _a6-o">Jan 07, 2024 2:00 pm</div></div>` +
+			box + `<h2>Me</h2><div><div><div></div><div><a href="your_instagram_activity/messages/inbox/alice_1/photos/x.jpg">` +
+			`<img src="your_instagram_activity/messages/inbox/alice_1/photos/x.jpg"/></a></div></div></div>` +
+			`<div class="_3-94 _a6-o">Jan 07, 2024 1:00 pm</div></div></body></html>`,
+	})
+	export, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+
+	at := func(month time.Month, day, hour, minute int) int64 {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.Local).Unix()
+	}
+	data := export.Instagram()
+	if len(data.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", data.Warnings)
+	}
+	if data.Username != "me" {
+		t.Fatalf("expected owner username, got %q", data.Username)
+	}
+	if len(data.Posts) != 3 {
+		t.Fatalf("expected 3 post files, got %+v", data.Posts)
+	}
+	first, video, second := data.Posts[0], data.Posts[1], data.Posts[2]
+	if first.Caption != "café & more #tag" || first.TakenAt != at(time.January, 2, 15, 4) || first.URL != "media/posts/202401/a.jpg" {
+		t.Fatalf("unexpected first post: %+v", first)
+	}
+	if !video.IsVideo || video.Caption != first.Caption || video.TakenAt != first.TakenAt {
+		t.Fatalf("unexpected carousel video: %+v", video)
+	}
+	if second.Caption != "" || second.TakenAt != at(time.January, 1, 9, 0) {
+		t.Fatalf("unexpected second post: %+v", second)
+	}
+	if len(data.Liked) != 1 || data.Liked[0].Shortcode != "LIKED" || data.Liked[0].Username != "bob" ||
+		data.Liked[0].TakenAt != at(time.January, 3, 10, 0) {
+		t.Fatalf("unexpected likes: %+v", data.Liked)
+	}
+	if len(data.Comments) != 1 || data.Comments[0].Text != "nice" || data.Comments[0].MediaOwner != "bob" ||
+		data.Comments[0].CreatedAt != at(time.January, 4, 11, 0) {
+		t.Fatalf("unexpected comments: %+v", data.Comments)
+	}
+	if len(data.Followers) != 1 || data.Followers[0].Username != "alice" {
+		t.Fatalf("unexpected followers: %+v", data.Followers)
+	}
+	if len(data.Following) != 1 || data.Following[0].Username != "carol" {
+		t.Fatalf("unexpected following: %+v", data.Following)
+	}
+	if len(data.Threads) != 1 {
+		t.Fatalf("expected 1 thread, got %+v", data.Threads)
+	}
+	conv := data.Threads[0]
+	if conv.Thread.ID != "inbox/alice_1" || conv.Thread.Title != "Alice" || len(conv.Thread.Participants) != 2 {
+		t.Fatalf("unexpected thread: %+v", conv.Thread)
+	}
+	if len(conv.Messages) != 2 || conv.Thread.LastActivityAt != at(time.January, 7, 14, 0) {
+		t.Fatalf("unexpected messages: %+v", conv.Messages)
+	}
+	photo, text := conv.Messages[0], conv.Messages[1]
+	if photo.Sender != "Me" || photo.Type != "media" || len(photo.Media) != 1 {
+		t.Fatalf("unexpected photo message: %+v", photo)
+	}
+	if text.Sender != "Alice" || text.Text != "hi 😂" || len(text.Reactions) != 1 ||
+		text.Reactions[0].Emoji != "❤" || text.Reactions[0].Sender != "Me" {
+		t.Fatalf("unexpected text message: %+v", text)
+	}
+}
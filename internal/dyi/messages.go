@@ -0,0 +1,137 @@
+package dyi
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/steipete/metcli/internal/instagram"
+)
+
+// Conversation is one message thread with its messages in chronological
+// order. DYI names participants by display name only.
+type Conversation struct {
+	Thread   instagram.Thread
+	Messages []instagram.Message
+}
+
+type rawThread struct {
+	Title        string           `json:"title"`
+	ThreadPath   string           `json:"thread_path"`
+	Participants []rawParticipant `json:"participants"`
+	Messages     []rawMessage     `json:"messages"`
+}
+
+type rawParticipant struct {
+	Name string `json:"name"`
+}
+
+type rawMessage struct {
+	SenderName  string        `json:"sender_name"`
+	TimestampMS int64         `json:"timestamp_ms"`
+	Content     string        `json:"content"`
+	Photos      []rawMedia    `json:"photos"`
+	Videos      []rawMedia    `json:"videos"`
+	Share       *rawShare     `json:"share"`
+	Reactions   []rawReaction `json:"reactions"`
+}
+
+type rawShare struct {
+	Link      string `json:"link"`
+	ShareText string `json:"share_text"`
+}
+
+type rawReaction struct {
+	Reaction string `json:"reaction"`
+	Actor    string `json:"actor"`
+}
+
+// conversations reads the message_N.json or message_N.html threads below
+// every folder matching dir (Instagram and Messenger share the format) and
+// merges split threads.
+func (e *Export) conversations(dir string) ([]Conversation, []string) {
+	var out []Conversation
+	var warnings []string
+	for _, name := range e.find(func(f string) bool {
+		return strings.Contains("/"+f, dir) && matchData(f, "message")
+	}) {
+		var thread rawThread
+		decode := e.decode
+		if path.Ext(name) == ".html" {
+			decode = e.decodeHTML
+		}
+		if err := decode(name, &thread); err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		out = mergeConversation(out, toConversation(thread, path.Base(path.Dir(name))))
+	}
+	for i := range out {
+		messages := out[i].Messages
+		sort.SliceStable(messages, func(a, b int) bool { return messages[a].Timestamp < messages[b].Timestamp })
+		if len(messages) > 0 {
+			out[i].Thread.LastActivityAt = messages[len(messages)-1].Timestamp
+		}
+	}
+	return out, warnings
+}
+
+func toConversation(raw rawThread, dir string) Conversation {
+	id := strings.TrimSpace(raw.ThreadPath)
+	if id == "" {
+		id = "inbox/" + dir
+	}
+	conv := Conversation{Thread: instagram.Thread{
+		ID:      id,
+		Title:   FixText(strings.TrimSpace(raw.Title)),
+		IsGroup: len(raw.Participants) > 2,
+	}}
+	for _, participant := range raw.Participants {
+		conv.Thread.Participants = append(conv.Thread.Participants, instagram.Account{
+			FullName: FixText(strings.TrimSpace(participant.Name)),
+		})
+	}
+	for _, msg := range raw.Messages {
+		conv.Messages = append(conv.Messages, toMessage(msg, id))
+	}
+	return conv
+}
+
+func toMessage(raw rawMessage, threadID string) instagram.Message {
+	msg := instagram.Message{
+		ThreadID:  threadID,
+		Sender:    FixText(strings.TrimSpace(raw.SenderName)),
+		Timestamp: raw.TimestampMS / 1000,
+		Type:      "text",
+		Text:      FixText(raw.Content),
+	}
+	for _, media := range append(append([]rawMedia{}, raw.Photos...), raw.Videos...) {
+		msg.Media = append(msg.Media, mediaItem(media, "", 0, msg.Sender))
+		msg.Type = "media"
+	}
+	if raw.Share != nil {
+		msg.Type = "share"
+		msg.LinkURL = strings.TrimSpace(raw.Share.Link)
+		if msg.Text == "" {
+			msg.Text = FixText(raw.Share.ShareText)
+		}
+	}
+	for _, reaction := range raw.Reactions {
+		msg.Reactions = append(msg.Reactions, instagram.Reaction{
+			Sender: FixText(strings.TrimSpace(reaction.Actor)),
+			Emoji:  FixText(reaction.Reaction),
+		})
+	}
+	return msg
+}
+
+// mergeConversation folds the message_N.json parts of one thread together.
+func mergeConversation(threads []Conversation, conv Conversation) []Conversation {
+	for i := range threads {
+		if threads[i].Thread.ID == conv.Thread.ID {
+			threads[i].Messages = append(threads[i].Messages, conv.Messages...)
+			return threads
+		}
+	}
+	return append(threads, conv)
+}
@@ -0,0 +1,81 @@
+package instagram
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// hashtagPattern finds tag candidates; hashtagStart decides whether one
+	// really starts a tag.
+	hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+	// A mention must not follow a word character or dot, so e-mail
+	// addresses are not mentions.
+	mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([A-Za-z0-9._]{1,30})`)
+)
+
+// Hashtags returns the hashtags of a caption without the leading #,
+// lowercased and deduplicated in order of appearance.
+func Hashtags(caption string) []string {
+	var tags []string
+	seen := map[string]struct{}{}
+	prevEnd := -1
+	for _, match := range hashtagPattern.FindAllStringSubmatchIndex(caption, -1) {
+		if !hashtagStart(caption, match[0], prevEnd) {
+			continue
+		}
+		prevEnd = match[1]
+		tag := strings.ToLower(caption[match[2]:match[3]])
+		if strings.Trim(tag, "0123456789") == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// hashtagStart reports whether the # at start begins a tag. It must not
+// follow a word character, so "a#b" and HTML entities such as "&#39;" are
+// not tags, unless that character ends the previous tag, as in "#sun#beach".
+func hashtagStart(caption string, start, prevEnd int) bool {
+	if start == 0 || start == prevEnd {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(caption[:start])
+	return prev == '#' || !(prev == '_' || prev == '&' || unicode.IsLetter(prev) || unicode.IsDigit(prev))
+}
+
+// Mentions returns the usernames mentioned in a caption without the leading
+// @, lowercased and deduplicated in order of appearance.
+func Mentions(caption string) []string {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(caption, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], "."))
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// Hashtags returns the hashtags of the item's caption.
+func (m MediaItem) Hashtags() []string {
+	return Hashtags(m.Caption)
+}
+
+// Mentions returns the usernames mentioned in the item's caption.
+func (m MediaItem) Mentions() []string {
+	return Mentions(m.Caption)
+}
@@ -0,0 +1,35 @@
+package instagram
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHashtags(t *testing.T) {
+	caption := "Summer #Launch2024 with #café vibes\n#launch2024 #1 mail&#39;s a#b (#tbt)"
+	want := []string{"launch2024", "café", "tbt"}
+	if got := Hashtags(caption); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := Hashtags("#sun#beach #sea ##double"); !reflect.DeepEqual(got, []string{"sun", "beach", "sea", "double"}) {
+		t.Fatalf("expected back-to-back tags, got %v", got)
+	}
+	if got := Hashtags("no tags here"); got != nil {
+		t.Fatalf("expected no tags, got %v", got)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	caption := "@Alice and @bob.smith. shot by @alice, mail me at me@example.com"
+	want := []string{"alice", "bob.smith"}
+	if got := Mentions(caption); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	item := MediaItem{Caption: "hi @carol #sun"}
+	if got := item.Mentions(); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Fatalf("unexpected item mentions: %v", got)
+	}
+	if got := item.Hashtags(); !reflect.DeepEqual(got, []string{"sun"}) {
+		t.Fatalf("unexpected item hashtags: %v", got)
+	}
+}
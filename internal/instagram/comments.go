@@ -0,0 +1,209 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Comment is one comment of a post. Replies holds its child comments; they
+// never nest further.
+type Comment struct {
+	ID         string
+	ParentID   string
+	Username   string
+	Text       string
+	CreatedAt  int64
+	LikeCount  int
+	ReplyCount int
+	Replies    []Comment
+	// MediaOwner is the author of the commented post when known; the API
+	// leaves it empty since the post is implied.
+	MediaOwner string
+}
+
+type commentsResponse struct {
+	Comments                []rawComment `json:"comments"`
+	HasMoreComments         bool         `json:"has_more_comments"`
+	HasMoreHeadloadComments bool         `json:"has_more_headload_comments"`
+	NextMaxID               string       `json:"next_max_id"`
+	NextMinID               string       `json:"next_min_id"`
+}
+
+type childCommentsResponse struct {
+	ChildComments           []rawComment `json:"child_comments"`
+	HasMoreTailChildComment bool         `json:"has_more_tail_child_comments"`
+	NextMaxChildCursor      string       `json:"next_max_child_cursor"`
+}
+
+type rawComment struct {
+	PK                   flexID       `json:"pk"`
+	ParentCommentID      flexID       `json:"parent_comment_id"`
+	Text                 string       `json:"text"`
+	CreatedAt            int64        `json:"created_at"`
+	User                 feedUser     `json:"user"`
+	CommentLikeCount     int          `json:"comment_like_count"`
+	ChildCommentCount    int          `json:"child_comment_count"`
+	PreviewChildComments []rawComment `json:"preview_child_comments"`
+}
+
+// FetchComments pages the comment thread of a media id, oldest page first as
+// the API serves it. max caps the number of top-level comments (0 = all).
+// With replies set, each comment's full reply thread is fetched as well;
+// otherwise only the previews the API inlines are kept.
+func FetchComments(
+	ctx context.Context,
+	mediaID string,
+	cookies CookieBundle,
+	max int,
+	replies bool,
+) ([]Comment, error) {
+	mediaID = strings.TrimSpace(mediaID)
+	if mediaID == "" {
+		return nil, fmt.Errorf("media id is required")
+	}
+
+	out := make([]Comment, 0)
+	cursor := commentCursor{}
+	for pageCount := 1; ; pageCount++ {
+		page, err := fetchCommentsPage(ctx, mediaID, cursor, cookies)
+		if err != nil {
+			return out, err
+		}
+		for _, comment := range page.comments {
+			if replies && comment.ReplyCount > len(comment.Replies) {
+				thread, err := FetchReplies(ctx, mediaID, comment.ID, cookies)
+				if err != nil {
+					return out, err
+				}
+				comment.Replies = thread
+			}
+			out = append(out, comment)
+			if max > 0 && len(out) >= max {
+				return out, nil
+			}
+		}
+		if !page.more || page.next == (commentCursor{}) || page.next == cursor || pageCount > 200 {
+			return out, nil
+		}
+		cursor = page.next
+	}
+}
+
+// FetchReplies returns every reply to one comment.
+func FetchReplies(ctx context.Context, mediaID, commentID string, cookies CookieBundle) ([]Comment, error) {
+	out := make([]Comment, 0)
+	maxID := ""
+	for pageCount := 1; ; pageCount++ {
+		endpoint := fmt.Sprintf(
+			"https://www.instagram.com/api/v1/media/%s/comments/%s/child_comments/",
+			url.PathEscape(mediaID),
+			url.PathEscape(commentID),
+		)
+		if maxID != "" {
+			endpoint += "?max_id=" + url.QueryEscape(maxID)
+		}
+		body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 4<<20)
+		if err != nil {
+			return out, fmt.Errorf("replies request failed (%d): %s", status, errText(err))
+		}
+		replies, more, next, err := decodeChildComments(body, commentID)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, replies...)
+		if !more || next == "" || next == maxID || pageCount > 200 {
+			return out, nil
+		}
+		maxID = next
+	}
+}
+
+// commentCursor is where the next comments page starts. The API pages
+// forward with min_id and falls back to max_id on some posts.
+type commentCursor struct {
+	minID string
+	maxID string
+}
+
+type commentsPage struct {
+	comments []Comment
+	more     bool
+	next     commentCursor
+}
+
+func fetchCommentsPage(ctx context.Context, mediaID string, cursor commentCursor, cookies CookieBundle) (commentsPage, error) {
+	query := url.Values{}
+	query.Set("can_support_threading", "true")
+	if cursor.minID != "" {
+		query.Set("min_id", cursor.minID)
+	}
+	if cursor.maxID != "" {
+		query.Set("max_id", cursor.maxID)
+	}
+	endpoint := fmt.Sprintf(
+		"https://www.instagram.com/api/v1/media/%s/comments/?%s",
+		url.PathEscape(mediaID),
+		query.Encode(),
+	)
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 4<<20)
+	if err != nil {
+		return commentsPage{}, fmt.Errorf("comments request failed (%d): %s", status, errText(err))
+	}
+	return decodeCommentsPage(body)
+}
+
+func decodeCommentsPage(body []byte) (commentsPage, error) {
+	var raw commentsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return commentsPage{}, err
+	}
+	comments := make([]Comment, 0, len(raw.Comments))
+	for _, item := range raw.Comments {
+		comment := toComment(item, "")
+		for _, preview := range item.PreviewChildComments {
+			comment.Replies = append(comment.Replies, toComment(preview, comment.ID))
+		}
+		comments = append(comments, comment)
+	}
+	next := commentCursor{}
+	if raw.HasMoreHeadloadComments && strings.TrimSpace(raw.NextMinID) != "" {
+		next.minID = strings.TrimSpace(raw.NextMinID)
+	} else if raw.HasMoreComments {
+		next.maxID = strings.TrimSpace(raw.NextMaxID)
+	}
+	return commentsPage{
+		comments: comments,
+		more:     next != (commentCursor{}),
+		next:     next,
+	}, nil
+}
+
+func decodeChildComments(body []byte, parentID string) ([]Comment, bool, string, error) {
+	var raw childCommentsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, false, "", err
+	}
+	out := make([]Comment, 0, len(raw.ChildComments))
+	for _, item := range raw.ChildComments {
+		out = append(out, toComment(item, parentID))
+	}
+	return out, raw.HasMoreTailChildComment, strings.TrimSpace(raw.NextMaxChildCursor), nil
+}
+
+func toComment(item rawComment, parentID string) Comment {
+	if parent := strings.TrimSpace(string(item.ParentCommentID)); parent != "" {
+		parentID = parent
+	}
+	return Comment{
+		ID:         strings.TrimSpace(string(item.PK)),
+		ParentID:   parentID,
+		Username:   strings.TrimSpace(item.User.Username),
+		Text:       item.Text,
+		CreatedAt:  item.CreatedAt,
+		LikeCount:  item.CommentLikeCount,
+		ReplyCount: item.ChildCommentCount,
+	}
+}
@@ -0,0 +1,55 @@
+package instagram
+
+import "testing"
+
+func TestDecodeCommentsPage(t *testing.T) {
+	body := []byte(`{
+		"comments": [
+			{"pk": 101, "text": "first", "created_at": 10, "user": {"username": "alice"},
+			 "comment_like_count": 3, "child_comment_count": 2,
+			 "preview_child_comments": [{"pk": "201", "parent_comment_id": "101", "text": "reply", "user": {"username": "bob"}}]},
+			{"pk": "102", "text": "second", "user": {"username": "carol"}}
+		],
+		"has_more_headload_comments": true,
+		"next_min_id": "{\"cursor\":\"x\"}"
+	}`)
+	page, err := decodeCommentsPage(body)
+	if err != nil {
+		t.Fatalf("decodeCommentsPage: %v", err)
+	}
+	if !page.more || page.next.minID == "" || page.next.maxID != "" {
+		t.Fatalf("unexpected cursor: %+v", page.next)
+	}
+	if len(page.comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(page.comments))
+	}
+	first := page.comments[0]
+	if first.ID != "101" || first.Username != "alice" || first.LikeCount != 3 || first.ReplyCount != 2 {
+		t.Fatalf("unexpected comment: %+v", first)
+	}
+	if len(first.Replies) != 1 || first.Replies[0].ParentID != "101" || first.Replies[0].Username != "bob" {
+		t.Fatalf("unexpected preview replies: %+v", first.Replies)
+	}
+}
+
+func TestDecodeCommentsPageEnd(t *testing.T) {
+	page, err := decodeCommentsPage([]byte(`{"comments": [], "has_more_comments": false, "next_max_id": "stale"}`))
+	if err != nil {
+		t.Fatalf("decodeCommentsPage: %v", err)
+	}
+	if page.more {
+		t.Fatalf("expected last page, got %+v", page.next)
+	}
+}
+
+func TestDecodeChildComments(t *testing.T) {
+	body := []byte(`{"child_comments": [{"pk": 301, "text": "r", "user": {"username": "dave"}}],
+		"has_more_tail_child_comments": true, "next_max_child_cursor": "c2"}`)
+	replies, more, next, err := decodeChildComments(body, "101")
+	if err != nil {
+		t.Fatalf("decodeChildComments: %v", err)
+	}
+	if !more || next != "c2" || len(replies) != 1 || replies[0].ParentID != "101" {
+		t.Fatalf("unexpected replies: %+v (%v, %q)", replies, more, next)
+	}
+}
@@ -0,0 +1,288 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const directInboxURL = "https://www.instagram.com/api/v1/direct_v2/inbox/"
+
+// Thread is a direct message conversation.
+type Thread struct {
+	ID             string
+	Title          string
+	Participants   []Account
+	LastActivityAt int64
+	IsGroup        bool
+}
+
+// Message is one item of a direct thread. Sender is empty for messages the
+// logged-in user sent; FromViewer tells them apart.
+type Message struct {
+	ID         string
+	ThreadID   string
+	SenderID   string
+	Sender     string
+	FromViewer bool
+	Timestamp  int64
+	Type       string
+	Text       string
+	LinkURL    string
+	Media      []MediaItem
+	Reactions  []Reaction
+}
+
+// Reaction is an emoji reaction (or like) on a message.
+type Reaction struct {
+	SenderID string
+	Sender   string
+	Emoji    string
+}
+
+type inboxResponse struct {
+	Inbox struct {
+		Threads      []rawThread `json:"threads"`
+		HasOlder     bool        `json:"has_older"`
+		OldestCursor string      `json:"oldest_cursor"`
+	} `json:"inbox"`
+}
+
+type threadResponse struct {
+	Thread rawThread `json:"thread"`
+}
+
+type rawThread struct {
+	ThreadID       string           `json:"thread_id"`
+	ThreadTitle    string           `json:"thread_title"`
+	Users          []friendshipUser `json:"users"`
+	LastActivityAt flexID           `json:"last_activity_at"`
+	IsGroup        bool             `json:"is_group"`
+	Items          []rawMessage     `json:"items"`
+	HasOlder       bool             `json:"has_older"`
+	OldestCursor   string           `json:"oldest_cursor"`
+}
+
+type rawMessage struct {
+	ItemID         string    `json:"item_id"`
+	UserID         flexID    `json:"user_id"`
+	Timestamp      flexID    `json:"timestamp"`
+	ItemType       string    `json:"item_type"`
+	Text           string    `json:"text"`
+	IsSentByViewer bool      `json:"is_sent_by_viewer"`
+	MediaShare     *feedItem `json:"media_share"`
+	Media          *feedItem `json:"media"`
+	Clip           *struct {
+		Clip feedItem `json:"clip"`
+	} `json:"clip"`
+	Link *struct {
+		Text        string `json:"text"`
+		LinkContext struct {
+			LinkURL string `json:"link_url"`
+		} `json:"link_context"`
+	} `json:"link"`
+	Like      string `json:"like"`
+	Reactions *struct {
+		Emojis []struct {
+			SenderID flexID `json:"sender_id"`
+			Emoji    string `json:"emoji"`
+		} `json:"emojis"`
+		Likes []struct {
+			SenderID flexID `json:"sender_id"`
+		} `json:"likes"`
+	} `json:"reactions"`
+}
+
+// FetchInbox lists direct threads, most recent activity first. max caps the
+// number of threads (0 = all).
+func FetchInbox(ctx context.Context, cookies CookieBundle, max int) ([]Thread, error) {
+	out := make([]Thread, 0)
+	cursor := ""
+	for pageCount := 1; ; pageCount++ {
+		query := url.Values{}
+		query.Set("persistentBadging", "true")
+		query.Set("limit", "20")
+		query.Set("thread_message_limit", "1")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		body, status, err := doJSONRequestWithLimit(ctx, directInboxURL+"?"+query.Encode(), "", cookies, 8<<20)
+		if err != nil {
+			return out, fmt.Errorf("inbox request failed (%d): %s", status, errText(err))
+		}
+		threads, more, next, err := decodeInbox(body)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, threads...)
+		if max > 0 && len(out) >= max {
+			return out[:max], nil
+		}
+		if !more || next == "" || next == cursor || pageCount > 200 {
+			return out, nil
+		}
+		cursor = next
+	}
+}
+
+// FetchThreadMessages pages a thread from its newest message backwards and
+// returns the messages in chronological order. max caps the number of
+// messages, keeping the newest (0 = all).
+func FetchThreadMessages(ctx context.Context, threadID string, cookies CookieBundle, max int) (Thread, []Message, error) {
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return Thread{}, nil, fmt.Errorf("thread id is required")
+	}
+
+	var thread Thread
+	out := make([]Message, 0)
+	cursor := ""
+	for pageCount := 1; ; pageCount++ {
+		endpoint := fmt.Sprintf("https://www.instagram.com/api/v1/direct_v2/threads/%s/?limit=20", url.PathEscape(threadID))
+		if cursor != "" {
+			endpoint += "&cursor=" + url.QueryEscape(cursor)
+		}
+		body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 8<<20)
+		if err != nil {
+			sortMessages(out)
+			return thread, out, fmt.Errorf("thread request failed (%d): %s", status, errText(err))
+		}
+		page, messages, more, next, err := decodeThread(body)
+		if err != nil {
+			sortMessages(out)
+			return thread, out, err
+		}
+		if pageCount == 1 {
+			thread = page
+		}
+		out = append(out, messages...)
+		if max > 0 && len(out) >= max {
+			out = out[:max]
+			break
+		}
+		if !more || next == "" || next == cursor || pageCount > 200 {
+			break
+		}
+		cursor = next
+	}
+	sortMessages(out)
+	return thread, out, nil
+}
+
+func sortMessages(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp < messages[j].Timestamp
+	})
+}
+
+func decodeInbox(body []byte) ([]Thread, bool, string, error) {
+	var raw inboxResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, false, "", err
+	}
+	out := make([]Thread, 0, len(raw.Inbox.Threads))
+	for _, thread := range raw.Inbox.Threads {
+		out = append(out, toThread(thread))
+	}
+	return out, raw.Inbox.HasOlder, strings.TrimSpace(raw.Inbox.OldestCursor), nil
+}
+
+func decodeThread(body []byte) (Thread, []Message, bool, string, error) {
+	var raw threadResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return Thread{}, nil, false, "", err
+	}
+	thread := toThread(raw.Thread)
+	usernames := map[string]string{}
+	for _, user := range thread.Participants {
+		usernames[user.ID] = user.Username
+	}
+	messages := make([]Message, 0, len(raw.Thread.Items))
+	for _, item := range raw.Thread.Items {
+		messages = append(messages, toMessage(item, thread.ID, usernames))
+	}
+	return thread, messages, raw.Thread.HasOlder, strings.TrimSpace(raw.Thread.OldestCursor), nil
+}
+
+func toThread(raw rawThread) Thread {
+	thread := Thread{
+		ID:             strings.TrimSpace(raw.ThreadID),
+		Title:          strings.TrimSpace(raw.ThreadTitle),
+		LastActivityAt: microsToUnix(raw.LastActivityAt),
+		IsGroup:        raw.IsGroup,
+	}
+	for _, user := range raw.Users {
+		thread.Participants = append(thread.Participants, Account{
+			ID:            strings.TrimSpace(string(user.PK)),
+			Username:      strings.TrimSpace(user.Username),
+			FullName:      strings.TrimSpace(user.FullName),
+			IsPrivate:     user.IsPrivate,
+			IsVerified:    user.IsVerified,
+			ProfilePicURL: strings.TrimSpace(user.ProfilePicURL),
+		})
+	}
+	return thread
+}
+
+func toMessage(raw rawMessage, threadID string, usernames map[string]string) Message {
+	senderID := strings.TrimSpace(string(raw.UserID))
+	msg := Message{
+		ID:         strings.TrimSpace(raw.ItemID),
+		ThreadID:   threadID,
+		SenderID:   senderID,
+		Sender:     usernames[senderID],
+		FromViewer: raw.IsSentByViewer,
+		Timestamp:  microsToUnix(raw.Timestamp),
+		Type:       raw.ItemType,
+		Text:       raw.Text,
+	}
+	if msg.FromViewer {
+		msg.Sender = ""
+	}
+
+	switch {
+	case raw.MediaShare != nil:
+		msg.Media = feedItemToMedia(*raw.MediaShare)
+	case raw.Clip != nil:
+		msg.Media = feedItemToMedia(raw.Clip.Clip)
+	case raw.Media != nil:
+		msg.Media = feedItemToMedia(*raw.Media)
+	}
+	if raw.Link != nil {
+		if msg.Text == "" {
+			msg.Text = raw.Link.Text
+		}
+		msg.LinkURL = strings.TrimSpace(raw.Link.LinkContext.LinkURL)
+	}
+	if msg.Text == "" && raw.Like != "" {
+		msg.Text = raw.Like
+	}
+
+	if raw.Reactions != nil {
+		for _, emoji := range raw.Reactions.Emojis {
+			id := strings.TrimSpace(string(emoji.SenderID))
+			msg.Reactions = append(msg.Reactions, Reaction{SenderID: id, Sender: usernames[id], Emoji: emoji.Emoji})
+		}
+		if len(raw.Reactions.Emojis) == 0 {
+			for _, like := range raw.Reactions.Likes {
+				id := strings.TrimSpace(string(like.SenderID))
+				msg.Reactions = append(msg.Reactions, Reaction{SenderID: id, Sender: usernames[id], Emoji: "❤️"})
+			}
+		}
+	}
+	return msg
+}
+
+// microsToUnix converts the microsecond timestamps of the direct API to unix
+// seconds.
+func microsToUnix(value flexID) int64 {
+	micros, err := strconv.ParseInt(strings.TrimSpace(string(value)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return micros / 1_000_000
+}
@@ -0,0 +1,62 @@
+package instagram
+
+import "testing"
+
+func TestDecodeInbox(t *testing.T) {
+	body := []byte(`{"inbox": {
+		"threads": [{"thread_id": "340", "thread_title": "alice", "last_activity_at": 1700000000123456,
+			"users": [{"pk": 1, "username": "alice", "full_name": "Alice"}]}],
+		"has_older": true,
+		"oldest_cursor": "c1"
+	}}`)
+	threads, more, next, err := decodeInbox(body)
+	if err != nil {
+		t.Fatalf("decodeInbox: %v", err)
+	}
+	if !more || next != "c1" || len(threads) != 1 {
+		t.Fatalf("unexpected inbox: %+v (%v, %q)", threads, more, next)
+	}
+	thread := threads[0]
+	if thread.ID != "340" || thread.LastActivityAt != 1700000000 || len(thread.Participants) != 1 {
+		t.Fatalf("unexpected thread: %+v", thread)
+	}
+}
+
+func TestDecodeThread(t *testing.T) {
+	body := []byte(`{"thread": {
+		"thread_id": "340",
+		"users": [{"pk": "1", "username": "alice"}],
+		"items": [
+			{"item_id": "m2", "user_id": 1, "timestamp": "1700000002000000", "item_type": "media_share",
+			 "media_share": {"media_type": 1, "code": "P1", "user": {"username": "author"},
+			  "image_versions2": {"candidates": [{"url": "img"}]}},
+			 "reactions": {"emojis": [{"sender_id": 2, "emoji": "😂"}]}},
+			{"item_id": "m1", "user_id": 2, "timestamp": 1700000001000000, "item_type": "text",
+			 "text": "hi", "is_sent_by_viewer": true}
+		],
+		"has_older": false
+	}}`)
+	thread, messages, more, _, err := decodeThread(body)
+	if err != nil {
+		t.Fatalf("decodeThread: %v", err)
+	}
+	if thread.ID != "340" || more || len(messages) != 2 {
+		t.Fatalf("unexpected thread: %+v (%d messages)", thread, len(messages))
+	}
+	shared := messages[0]
+	if shared.Sender != "alice" || shared.Timestamp != 1700000002 || len(shared.Media) != 1 || shared.Media[0].Shortcode != "P1" {
+		t.Fatalf("unexpected shared post: %+v", shared)
+	}
+	if len(shared.Reactions) != 1 || shared.Reactions[0].Emoji != "😂" {
+		t.Fatalf("unexpected reactions: %+v", shared.Reactions)
+	}
+	mine := messages[1]
+	if !mine.FromViewer || mine.Sender != "" || mine.Text != "hi" {
+		t.Fatalf("unexpected own message: %+v", mine)
+	}
+
+	sortMessages(messages)
+	if messages[0].ID != "m1" {
+		t.Fatalf("expected chronological order, got %q first", messages[0].ID)
+	}
+}
@@ -0,0 +1,130 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SaveMedia streams mediaURL into dest, sending the same cookie and Referer
+// headers as DownloadImage. The file is written to a temporary sibling first
+// so an interrupted download never leaves a truncated file behind.
+func SaveMedia(
+	ctx context.Context,
+	client *http.Client,
+	mediaURL string,
+	username string,
+	cookies CookieBundle,
+	dest string,
+) (int64, error) {
+	req, err := newMediaRequest(ctx, mediaURL, "*/*", username, cookies)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("fetch %s: %d %s", mediaURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".metcli-*")
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(tmp, resp.Body)
+	if err == nil {
+		// CreateTemp makes the file owner-only; downloads get the usual mode.
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return written, err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		_ = os.Remove(tmp.Name())
+		return written, err
+	}
+	return written, nil
+}
+
+// DownloadClient is ImageClient with a timeout suited to full-resolution
+// files rather than grid thumbnails.
+func DownloadClient() *http.Client {
+	return &http.Client{Timeout: 5 * time.Minute}
+}
+
+// MediaFileName derives a stable file name for item from its shortcode and the
+// CDN file name, which survives the signed query parameters changing.
+func MediaFileName(item Item) string {
+	base := cdnBaseName(item.DownloadURL())
+	if path.Ext(base) == "" {
+		if item.IsVideo {
+			base += ".mp4"
+		} else {
+			base += ".jpg"
+		}
+	}
+
+	prefix := strings.TrimSpace(item.Shortcode)
+	if item.Kind == KindAvatar {
+		prefix = "avatar"
+	}
+	if prefix != "" {
+		base = prefix + "_" + base
+	}
+	return SanitizeFileName(base)
+}
+
+// MediaKey identifies a media file across fetches: the shortcode plus the CDN
+// file name, since the signed URL query changes on every request.
+func MediaKey(media MediaItem) string {
+	return strings.TrimSpace(media.Shortcode) + "/" + cdnBaseName(media.URL)
+}
+
+func cdnBaseName(mediaURL string) string {
+	base := ""
+	if parsed, err := url.Parse(mediaURL); err == nil {
+		base = path.Base(parsed.Path)
+	}
+	if base == "" || base == "." || base == "/" {
+		base = "media"
+	}
+	return base
+}
+
+// SanitizeFileName maps name onto a portable file name made of ASCII letters,
+// digits, '.', '-' and '_'.
+func SanitizeFileName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	out := strings.TrimLeft(b.String(), ".")
+	if strings.Trim(out, "_") == "" {
+		return "untitled"
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package instagram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMediaFileName(t *testing.T) {
+	cases := []struct {
+		item Item
+		want string
+	}{
+		{
+			item: Item{Kind: "media", MediaItem: MediaItem{URL: "https://cdn.example/v/t51/123_456_n.jpg?stp=dst&_nc_ht=x", Shortcode: "abc"}},
+			want: "abc_123_456_n.jpg",
+		},
+		{
+			item: Item{Kind: "avatar", MediaItem: MediaItem{URL: "https://cdn.example/pic/999_n.jpg"}},
+			want: "avatar_999_n.jpg",
+		},
+		{
+			item: Item{Kind: "media", MediaItem: MediaItem{URL: "https://cdn.example/video", Shortcode: "vid", IsVideo: true}},
+			want: "vid_video.mp4",
+		},
+		{
+			item: Item{Kind: "media", MediaItem: MediaItem{URL: "https://cdn.example/thumb.jpg", VideoURL: "https://cdn.example/clip_n.mp4?x=1", Shortcode: "vid", IsVideo: true}},
+			want: "vid_clip_n.mp4",
+		},
+		{
+			item: Item{Kind: "media", MediaItem: MediaItem{URL: "https://cdn.example/a/b c.webp", Shortcode: "../x"}},
+			want: "_x_b_c.webp",
+		},
+	}
+	for _, tc := range cases {
+		if got := MediaFileName(tc.item); got != tc.want {
+			t.Fatalf("MediaFileName(%q) = %q, want %q", tc.item.URL, got, tc.want)
+		}
+	}
+}
+
+func TestSaveMedia(t *testing.T) {
+	var referer, cookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		referer = r.Header.Get("Referer")
+		cookie = r.Header.Get("Cookie")
+		_, _ = w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "out.jpg")
+	n, err := SaveMedia(context.Background(), server.Client(), server.URL+"/x.jpg", "tester", CookieBundle{Header: "sessionid=1"}, dest)
+	if err != nil {
+		t.Fatalf("SaveMedia: %v", err)
+	}
+	if n != int64(len("payload")) {
+		t.Fatalf("expected %d bytes, got %d", len("payload"), n)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "payload" {
+		t.Fatalf("unexpected file contents %q (%v)", data, err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o644 {
+		t.Fatalf("expected mode 0644, got %v", perm)
+	}
+	if referer != "https://www.instagram.com/tester/" || cookie != "sessionid=1" {
+		t.Fatalf("unexpected headers referer=%q cookie=%q", referer, cookie)
+	}
+}
+
+func TestSaveMediaErrorLeavesNoFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "out.jpg")
+	if _, err := SaveMedia(context.Background(), server.Client(), server.URL, "", CookieBundle{}, dest); err == nil {
+		t.Fatalf("expected error for 403")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("expected empty dir, got %d entries", len(entries))
+	}
+}
@@ -32,22 +32,82 @@ type feedCaption struct {
 }
 
 type feedItem struct {
-	MediaType     int             `json:"media_type"`
-	ImageVersions imageVersions   `json:"image_versions2"`
-	CarouselMedia []carouselMedia `json:"carousel_media"`
-	ThumbnailURL  string          `json:"thumbnail_url"`
-	Code          string          `json:"code"`
-	Shortcode     string          `json:"shortcode"`
-	TakenAt       int64           `json:"taken_at"`
-	User          feedUser        `json:"user"`
-	Caption       *feedCaption    `json:"caption"`
-	CaptionText   string          `json:"caption_text"`
+	MediaType             int             `json:"media_type"`
+	ImageVersions         imageVersions   `json:"image_versions2"`
+	CarouselMedia         []carouselMedia `json:"carousel_media"`
+	ThumbnailURL          string          `json:"thumbnail_url"`
+	Code                  string          `json:"code"`
+	Shortcode             string          `json:"shortcode"`
+	TakenAt               int64           `json:"taken_at"`
+	User                  feedUser        `json:"user"`
+	Caption               *feedCaption    `json:"caption"`
+	CaptionText           string          `json:"caption_text"`
+	TimelinePinnedUserIDs []int64         `json:"timeline_pinned_user_ids"`
+	ClipsTabPinnedUserIDs []int64         `json:"clips_tab_pinned_user_ids"`
+	VideoVersions         []videoVersion  `json:"video_versions"`
+	VideoDuration         float64         `json:"video_duration"`
+	OriginalWidth         int             `json:"original_width"`
+	OriginalHeight        int             `json:"original_height"`
+	ExpiringAt            int64           `json:"expiring_at"`
+	PlayCount             int64           `json:"play_count"`
+	IGPlayCount           int64           `json:"ig_play_count"`
+	ViewCount             int64           `json:"view_count"`
+	ClipsMetadata         *clipsMetadata  `json:"clips_metadata"`
+	PK                    flexID          `json:"pk"`
+	LikeCount             int64           `json:"like_count"`
+	CommentCount          int64           `json:"comment_count"`
+	Location              *feedLocation   `json:"location"`
+	Lat                   float64         `json:"lat"`
+	Lng                   float64         `json:"lng"`
+	AccessibilityCaption  string          `json:"accessibility_caption"`
+	Usertags              *usertags       `json:"usertags"`
+	CoauthorProducers     []feedUser      `json:"coauthor_producers"`
+	ProductType           string          `json:"product_type"`
+}
+
+type feedLocation struct {
+	PK   flexID  `json:"pk"`
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lng  float64 `json:"lng"`
+}
+
+type usertags struct {
+	In []struct {
+		User feedUser `json:"user"`
+	} `json:"in"`
+}
+
+type clipsMetadata struct {
+	MusicInfo *struct {
+		MusicAssetInfo struct {
+			Title         string `json:"title"`
+			DisplayArtist string `json:"display_artist"`
+		} `json:"music_asset_info"`
+	} `json:"music_info"`
+	OriginalSoundInfo *struct {
+		OriginalAudioTitle string   `json:"original_audio_title"`
+		IGArtist           feedUser `json:"ig_artist"`
+	} `json:"original_sound_info"`
 }
 
 type carouselMedia struct {
-	MediaType     int           `json:"media_type"`
-	ImageVersions imageVersions `json:"image_versions2"`
-	ThumbnailURL  string        `json:"thumbnail_url"`
+	PK                   flexID         `json:"pk"`
+	AccessibilityCaption string         `json:"accessibility_caption"`
+	Usertags             *usertags      `json:"usertags"`
+	MediaType            int            `json:"media_type"`
+	ImageVersions        imageVersions  `json:"image_versions2"`
+	ThumbnailURL         string         `json:"thumbnail_url"`
+	VideoVersions        []videoVersion `json:"video_versions"`
+	VideoDuration        float64        `json:"video_duration"`
+	OriginalWidth        int            `json:"original_width"`
+	OriginalHeight       int            `json:"original_height"`
+}
+
+type videoVersion struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type imageVersions struct {
@@ -65,77 +125,93 @@ func FetchUserMedia(
 	username string,
 	profile Profile,
 	cookies CookieBundle,
-	max int,
+	limit Limit,
 	pageSize int,
 ) ([]MediaItem, error) {
-	if max == 0 {
-		max = -1
-	}
+	out := make([]MediaItem, 0, len(profile.Media))
+	err := StreamUserMedia(ctx, username, profile, cookies, limit, pageSize, func(item MediaItem) error {
+		out = append(out, item)
+		return nil
+	})
+	return out, err
+}
+
+// StreamUserMedia hands the posts of an account to onItem as each feed page
+// arrives. The feed is newest first, so paging stops once it passes
+// limit.Since.
+func StreamUserMedia(
+	ctx context.Context,
+	username string,
+	profile Profile,
+	cookies CookieBundle,
+	limit Limit,
+	pageSize int,
+	onItem func(MediaItem) error,
+) error {
 	if pageSize <= 0 {
 		pageSize = 50
 	}
 	if pageSize > 50 {
 		pageSize = 50
 	}
+	userID := strings.TrimSpace(profile.UserID)
+	return streamUserMedia(ctx, username, profile, limit, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchUserFeedPage(ctx, username, userID, maxID, pageSize, cookies)
+	}, onItem)
+}
 
-	out := make([]MediaItem, 0, len(profile.Media))
-	seen := map[string]struct{}{}
-	appendUnique := func(items []MediaItem) {
-		for _, item := range items {
-			if item.URL == "" {
-				continue
-			}
-			if _, ok := seen[item.URL]; ok {
-				continue
+// streamUserMedia pages fetch for the account's feed. The profile page only
+// carries a cover image per post, without carousel slides, so its posts are
+// used only when there is no user id to page with or the first feed page
+// fails.
+func streamUserMedia(
+	ctx context.Context,
+	username string,
+	profile Profile,
+	limit Limit,
+	fetch pageFetcher,
+	onItem func(MediaItem) error,
+) error {
+	withUsername := func(items []MediaItem) []MediaItem {
+		if strings.TrimSpace(username) == "" {
+			return items
+		}
+		for i := range items {
+			if strings.TrimSpace(items[i].Username) == "" {
+				items[i].Username = username
 			}
-			seen[item.URL] = struct{}{}
-			out = append(out, item)
 		}
+		return items
 	}
 
-	appendUnique(profile.Media)
-	if max > 0 && len(out) >= max {
-		return out[:max], nil
+	stream := newFeedStream(limit, onItem)
+	// The profile's own posts are only checked against the window: pinned
+	// posts lead them out of order and are not always marked as such.
+	fallback := func() error {
+		stream.chronological = false
+		_, err := stream.emit(withUsername(append([]MediaItem(nil), profile.Media...)))
+		return err
 	}
-
-	userID := strings.TrimSpace(profile.UserID)
-	if userID == "" {
-		return out, nil
+	if strings.TrimSpace(profile.UserID) == "" {
+		return fallback()
 	}
 
-	maxID := ""
-	pageCount := 0
-	for {
-		pageCount++
-		page, err := fetchUserFeedPage(ctx, username, userID, maxID, pageSize, cookies)
-		if err != nil {
-			return out, err
-		}
-		appendUnique(page.items)
-		if max > 0 && len(out) >= max {
-			return out[:max], nil
-		}
-		if !page.moreAvailable || page.nextMaxID == "" {
-			break
-		}
-		if page.nextMaxID == maxID {
-			break
+	stream.chronological = true
+	fetched := false
+	err := stream.run(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		page, err := fetch(ctx, maxID)
+		if err == nil {
+			fetched = true
 		}
-		maxID = page.nextMaxID
-		if pageCount > 200 {
-			break
+		page.items = withUsername(page.items)
+		return page, err
+	})
+	if err != nil && !fetched {
+		if fallbackErr := fallback(); fallbackErr != nil {
+			return fallbackErr
 		}
 	}
-
-	if strings.TrimSpace(username) != "" {
-		for i := range out {
-			if strings.TrimSpace(out[i].Username) == "" {
-				out[i].Username = username
-			}
-		}
-	}
-
-	return out, nil
+	return err
 }
 
 type feedPage struct {
@@ -144,6 +220,9 @@ type feedPage struct {
 	nextMaxID     string
 }
 
+// pageFetcher loads one page of a max_id paginated feed.
+type pageFetcher func(ctx context.Context, maxID string) (feedPage, error)
+
 func fetchUserFeedPage(
 	ctx context.Context,
 	username string,
@@ -172,34 +251,15 @@ func fetchUserFeedPage(
 		return feedPage{}, fmt.Errorf("feed request failed (%d): %s", status, errText(err))
 	}
 
-	var raw feedResponse
-	if err := json.Unmarshal(body, &raw); err != nil {
-		return feedPage{}, err
-	}
-
-	items := make([]MediaItem, 0, len(raw.Items))
-	for _, item := range raw.Items {
-		items = append(items, feedItemToMedia(item)...)
-	}
-
-	return feedPage{
-		items:         items,
-		moreAvailable: raw.MoreAvailable,
-		nextMaxID:     raw.NextMaxID,
-	}, nil
+	return decodeFeedPage(body)
 }
 
 func feedItemToMedia(item feedItem) []MediaItem {
-	shortcode := item.Code
-	if shortcode == "" {
-		shortcode = item.Shortcode
-	}
-	username := strings.TrimSpace(item.User.Username)
-	caption := itemCaption(item)
+	post := postMedia(item)
 
 	switch item.MediaType {
 	case 8:
-		return expandCarousel(item, shortcode, username, caption)
+		return expandCarousel(item, post)
 	case 2:
 		url := strings.TrimSpace(item.ThumbnailURL)
 		if url == "" {
@@ -208,30 +268,127 @@ func feedItemToMedia(item feedItem) []MediaItem {
 		if url == "" {
 			return nil
 		}
-		return []MediaItem{{
-			URL:       url,
-			IsVideo:   true,
-			Shortcode: shortcode,
-			TakenAt:   item.TakenAt,
-			Username:  username,
-			Caption:   caption,
-		}}
+		media := post
+		media.URL = url
+		media.IsVideo = true
+		media.Width, media.Height = item.OriginalWidth, item.OriginalHeight
+		media.VideoDuration = item.VideoDuration
+		applyBestVideo(&media, item.VideoVersions, item.OriginalWidth, item.OriginalHeight)
+		return []MediaItem{media}
 	default:
 		url := pickBestCandidate(item.ImageVersions.Candidates)
 		if url == "" {
 			return nil
 		}
-		return []MediaItem{{
-			URL:       url,
-			IsVideo:   false,
-			Shortcode: shortcode,
-			TakenAt:   item.TakenAt,
-			Username:  username,
-			Caption:   caption,
-		}}
+		media := post
+		media.URL = url
+		media.Width, media.Height = imageSize(item.ImageVersions.Candidates, item.OriginalWidth, item.OriginalHeight)
+		return []MediaItem{media}
 	}
 }
 
+// postMedia returns the fields shared by every file of a post; callers fill
+// in the URL and per-file details.
+func postMedia(item feedItem) MediaItem {
+	shortcode := item.Code
+	if shortcode == "" {
+		shortcode = item.Shortcode
+	}
+	playCount := item.PlayCount
+	if playCount == 0 {
+		playCount = item.IGPlayCount
+	}
+	audioTitle, audioArtist := itemAudio(item)
+	return MediaItem{
+		Shortcode:   shortcode,
+		TakenAt:     item.TakenAt,
+		Username:    strings.TrimSpace(item.User.Username),
+		Caption:     itemCaption(item),
+		Pinned:      len(item.TimelinePinnedUserIDs) > 0,
+		ExpiringAt:  item.ExpiringAt,
+		PlayCount:   playCount,
+		ViewCount:   item.ViewCount,
+		AudioTitle:  audioTitle,
+		AudioArtist: audioArtist,
+
+		MediaID:              mediaID(item.PK),
+		LikeCount:            item.LikeCount,
+		CommentCount:         item.CommentCount,
+		Location:             itemLocation(item),
+		AccessibilityCaption: strings.TrimSpace(item.AccessibilityCaption),
+		TaggedUsers:          item.Usertags.usernames(),
+		Coauthors:            coauthors(item),
+		ProductType:          item.ProductType,
+	}
+}
+
+// mediaID trims the "_<owner id>" suffix some payloads append to the pk.
+func mediaID(pk flexID) string {
+	id, _, _ := strings.Cut(strings.TrimSpace(string(pk)), "_")
+	return id
+}
+
+func itemLocation(item feedItem) *Location {
+	if item.Location == nil || strings.TrimSpace(item.Location.Name) == "" {
+		return nil
+	}
+	location := &Location{
+		ID:   strings.TrimSpace(string(item.Location.PK)),
+		Name: strings.TrimSpace(item.Location.Name),
+		Lat:  item.Location.Lat,
+		Lng:  item.Location.Lng,
+	}
+	if location.Lat == 0 && location.Lng == 0 {
+		location.Lat, location.Lng = item.Lat, item.Lng
+	}
+	return location
+}
+
+func (tags *usertags) usernames() []string {
+	if tags == nil {
+		return nil
+	}
+	var out []string
+	for _, tag := range tags.In {
+		if name := strings.TrimSpace(tag.User.Username); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// coauthors lists collab post co-authors other than the posting account.
+func coauthors(item feedItem) []string {
+	owner := strings.TrimSpace(item.User.Username)
+	var out []string
+	for _, user := range item.CoauthorProducers {
+		if name := strings.TrimSpace(user.Username); name != "" && name != owner {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// itemAudio returns the licensed track of a reel, or its original sound when
+// it uses none.
+func itemAudio(item feedItem) (string, string) {
+	meta := item.ClipsMetadata
+	if meta == nil {
+		return "", ""
+	}
+	if meta.MusicInfo != nil {
+		info := meta.MusicInfo.MusicAssetInfo
+		if title := strings.TrimSpace(info.Title); title != "" {
+			return title, strings.TrimSpace(info.DisplayArtist)
+		}
+	}
+	if meta.OriginalSoundInfo != nil {
+		info := meta.OriginalSoundInfo
+		return strings.TrimSpace(info.OriginalAudioTitle), strings.TrimSpace(info.IGArtist.Username)
+	}
+	return "", ""
+}
+
 func itemCaption(item feedItem) string {
 	if item.Caption != nil {
 		return strings.TrimSpace(item.Caption.Text)
@@ -242,12 +399,9 @@ func itemCaption(item feedItem) string {
 func FetchHomeFeed(
 	ctx context.Context,
 	cookies CookieBundle,
-	max int,
+	limit Limit,
 	pageSize int,
 ) ([]MediaItem, error) {
-	if max == 0 {
-		max = -1
-	}
 	if pageSize <= 0 {
 		pageSize = 50
 	}
@@ -255,59 +409,211 @@ func FetchHomeFeed(
 		pageSize = 50
 	}
 
-	out := make([]MediaItem, 0, pageSize)
-	seen := map[string]struct{}{}
-	appendUnique := func(items []MediaItem) {
-		for _, item := range items {
-			if item.URL == "" {
-				continue
+	return collectFeed(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchHomeFeedPage(ctx, maxID, pageSize, cookies)
+	}, limit)
+}
+
+// Limit caps a fetch. Items counts files, so every carousel slide counts;
+// Posts counts posts, so a carousel counts once. Zero means no cap, and
+// whichever cap is reached first applies.
+//
+// Since and Until bound TakenAt in unix seconds (Since inclusive, Until
+// exclusive; zero leaves that side open). Match, when set, keeps only the
+// items it accepts. Items left out either way do not count towards the caps.
+type Limit struct {
+	Items int
+	Posts int
+	Since int64
+	Until int64
+	Match func(MediaItem) bool
+}
+
+// Includes reports whether item was taken inside the window and passes
+// Match. Items without a timestamp, such as profile pictures, are never
+// outside the window.
+func (l Limit) Includes(item MediaItem) bool {
+	if l.Match != nil && !l.Match(item) {
+		return false
+	}
+	if item.TakenAt <= 0 {
+		return true
+	}
+	if l.Since > 0 && item.TakenAt < l.Since {
+		return false
+	}
+	if l.Until > 0 && item.TakenAt >= l.Until {
+		return false
+	}
+	return true
+}
+
+// before reports whether item predates the window, so that nothing after it
+// in a reverse-chronological feed can be inside it. Pinned posts sit at the
+// top regardless of age and never count.
+func (l Limit) before(item MediaItem) bool {
+	return l.Since > 0 && !item.Pinned && item.TakenAt > 0 && item.TakenAt < l.Since
+}
+
+// Apply drops media that Includes rejects, cuts the rest at the limit and
+// reports whether it was reached. Posts are only ever kept whole.
+func (l Limit) Apply(media []MediaItem) ([]MediaItem, bool) {
+	if l.Since > 0 || l.Until > 0 || l.Match != nil {
+		kept := make([]MediaItem, 0, len(media))
+		for _, item := range media {
+			if l.Includes(item) {
+				kept = append(kept, item)
 			}
-			if _, ok := seen[item.URL]; ok {
-				continue
+		}
+		media = kept
+	}
+	n, reached := l.cut(len(media), func(i int) MediaItem { return media[i] })
+	return media[:n], reached
+}
+
+// ApplyItems is Apply for tagged items. Profile pictures are not posts: they
+// are always kept and never count towards the caps.
+func (l Limit) ApplyItems(items []Item) []Item {
+	var avatars, rest []Item
+	for _, item := range items {
+		switch {
+		case item.Kind == KindAvatar:
+			avatars = append(avatars, item)
+		case l.Includes(item.MediaItem):
+			rest = append(rest, item)
+		}
+	}
+	n, _ := l.cut(len(rest), func(i int) MediaItem { return rest[i].MediaItem })
+	return append(avatars, rest[:n]...)
+}
+
+func (l Limit) cut(n int, at func(int) MediaItem) (int, bool) {
+	reached := false
+	if l.Items > 0 && n >= l.Items {
+		n, reached = l.Items, true
+	}
+	if l.Posts <= 0 {
+		return n, reached
+	}
+	seen := map[string]struct{}{}
+	for i := 0; i < n; i++ {
+		key := PostKey(at(i))
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if len(seen) == l.Posts {
+			return i, true
+		}
+		seen[key] = struct{}{}
+	}
+	return n, reached || len(seen) == l.Posts
+}
+
+// collectFeed pages fetch from the top, dropping items without a URL and
+// repeats, until the feed ends, the limit is reached or the page budget runs
+// out. Items gathered before an error are returned with it.
+func collectFeed(ctx context.Context, fetch pageFetcher, limit Limit) ([]MediaItem, error) {
+	out := make([]MediaItem, 0)
+	err := newFeedStream(limit, func(item MediaItem) error {
+		out = append(out, item)
+		return nil
+	}).run(ctx, fetch)
+	return out, err
+}
+
+// feedStream hands feed items to onItem once each, in feed order, until its
+// limit is reached. When chronological, the feed is newest first and the
+// stream ends at the first item older than limit.Since.
+type feedStream struct {
+	limit         Limit
+	chronological bool
+	onItem        func(MediaItem) error
+	seen          map[string]struct{}
+	posts         map[string]struct{}
+	count         int
+}
+
+func newFeedStream(limit Limit, onItem func(MediaItem) error) *feedStream {
+	return &feedStream{
+		limit:  limit,
+		onItem: onItem,
+		seen:   map[string]struct{}{},
+		posts:  map[string]struct{}{},
+	}
+}
+
+// emit passes items on, skipping those without a URL and repeats, and
+// reports whether the limit is reached. Posts are only ever passed whole, as
+// a page never splits one.
+func (s *feedStream) emit(items []MediaItem) (bool, error) {
+	for _, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		if _, ok := s.seen[item.URL]; ok {
+			continue
+		}
+		s.seen[item.URL] = struct{}{}
+		if s.chronological && s.limit.before(item) {
+			return true, nil
+		}
+		if !s.limit.Includes(item) {
+			continue
+		}
+		if s.limit.Items > 0 && s.count >= s.limit.Items {
+			return true, nil
+		}
+		if s.limit.Posts > 0 {
+			key := PostKey(item)
+			if _, ok := s.posts[key]; !ok {
+				if len(s.posts) >= s.limit.Posts {
+					return true, nil
+				}
+				s.posts[key] = struct{}{}
 			}
-			seen[item.URL] = struct{}{}
-			out = append(out, item)
 		}
+		if err := s.onItem(item); err != nil {
+			return true, err
+		}
+		s.count++
 	}
+	reached := (s.limit.Items > 0 && s.count >= s.limit.Items) ||
+		(s.limit.Posts > 0 && len(s.posts) >= s.limit.Posts)
+	return reached, nil
+}
 
+// run pages fetch from the top until the feed ends, the limit is reached or
+// the page budget runs out.
+func (s *feedStream) run(ctx context.Context, fetch pageFetcher) error {
 	maxID := ""
-	pageCount := 0
-	for {
-		pageCount++
-		page, err := fetchHomeFeedPage(ctx, maxID, pageSize, cookies)
+	for pageCount := 1; ; pageCount++ {
+		page, err := fetch(ctx, maxID)
 		if err != nil {
-			return out, err
+			return err
 		}
-		appendUnique(page.items)
-		if max > 0 && len(out) >= max {
-			return out[:max], nil
+		if reached, err := s.emit(page.items); err != nil || reached {
+			return err
 		}
 		if !page.moreAvailable || page.nextMaxID == "" {
-			break
+			return nil
 		}
 		if page.nextMaxID == maxID {
-			break
+			return nil
 		}
 		maxID = page.nextMaxID
 		if pageCount > 200 {
-			break
+			return nil
 		}
 	}
-
-	return out, nil
 }
 
 func StreamHomeFeed(
 	ctx context.Context,
 	cookies CookieBundle,
-	max int,
+	limit Limit,
 	pageSize int,
-	includeVideos bool,
 	onItem func(MediaItem) error,
 ) (int, error) {
-	if max == 0 {
-		max = -1
-	}
 	if pageSize <= 0 {
 		pageSize = 50
 	}
@@ -318,48 +624,11 @@ func StreamHomeFeed(
 		return 0, nil
 	}
 
-	seen := map[string]struct{}{}
-	count := 0
-	maxID := ""
-	pageCount := 0
-	for {
-		pageCount++
-		page, err := fetchHomeFeedPage(ctx, maxID, pageSize, cookies)
-		if err != nil {
-			return count, err
-		}
-		for _, item := range page.items {
-			if item.URL == "" {
-				continue
-			}
-			if item.IsVideo && !includeVideos {
-				continue
-			}
-			if _, ok := seen[item.URL]; ok {
-				continue
-			}
-			seen[item.URL] = struct{}{}
-			if err := onItem(item); err != nil {
-				return count, err
-			}
-			count++
-			if max > 0 && count >= max {
-				return count, nil
-			}
-		}
-		if !page.moreAvailable || page.nextMaxID == "" {
-			break
-		}
-		if page.nextMaxID == maxID {
-			break
-		}
-		maxID = page.nextMaxID
-		if pageCount > 200 {
-			break
-		}
-	}
-
-	return count, nil
+	stream := newFeedStream(limit, onItem)
+	err := stream.run(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchHomeFeedPage(ctx, maxID, pageSize, cookies)
+	})
+	return stream.count, err
 }
 
 func fetchHomeFeedPage(
@@ -384,6 +653,12 @@ func fetchHomeFeedPage(
 		return feedPage{}, fmt.Errorf("home feed request failed (%d): %s", status, errText(err))
 	}
 
+	return decodeFeedPage(body)
+}
+
+// decodeFeedPage reads a max_id paginated feed whose posts sit in "items", or
+// in wrapped "feed_items" entries as the timeline sometimes sends them.
+func decodeFeedPage(body []byte) (feedPage, error) {
 	var raw feedResponse
 	if err := json.Unmarshal(body, &raw); err != nil {
 		return feedPage{}, err
@@ -395,13 +670,7 @@ func fetchHomeFeedPage(
 	}
 	if len(items) == 0 && len(raw.FeedItems) > 0 {
 		for _, entry := range raw.FeedItems {
-			if entry.MediaOrAd.Code != "" || entry.MediaOrAd.Shortcode != "" || entry.MediaOrAd.MediaType != 0 {
-				items = append(items, feedItemToMedia(entry.MediaOrAd)...)
-				continue
-			}
-			if entry.Media.Code != "" || entry.Media.Shortcode != "" || entry.Media.MediaType != 0 {
-				items = append(items, feedItemToMedia(entry.Media)...)
-			}
+			items = append(items, entryMedia(entry)...)
 		}
 	}
 
@@ -412,9 +681,21 @@ func fetchHomeFeedPage(
 	}, nil
 }
 
-func expandCarousel(item feedItem, shortcode, username, caption string) []MediaItem {
+// entryMedia maps a feed entry that wraps its post in "media_or_ad" or
+// "media" rather than carrying it inline.
+func entryMedia(entry feedEntry) []MediaItem {
+	if entry.MediaOrAd.Code != "" || entry.MediaOrAd.Shortcode != "" || entry.MediaOrAd.MediaType != 0 {
+		return feedItemToMedia(entry.MediaOrAd)
+	}
+	if entry.Media.Code != "" || entry.Media.Shortcode != "" || entry.Media.MediaType != 0 {
+		return feedItemToMedia(entry.Media)
+	}
+	return nil
+}
+
+func expandCarousel(item feedItem, post MediaItem) []MediaItem {
 	items := make([]MediaItem, 0, len(item.CarouselMedia))
-	for _, media := range item.CarouselMedia {
+	for i, media := range item.CarouselMedia {
 		isVideo := media.MediaType == 2
 		url := ""
 		if isVideo {
@@ -426,18 +707,66 @@ func expandCarousel(item feedItem, shortcode, username, caption string) []MediaI
 		if url == "" {
 			continue
 		}
-		items = append(items, MediaItem{
-			URL:       url,
-			IsVideo:   isVideo,
-			Shortcode: shortcode,
-			TakenAt:   item.TakenAt,
-			Username:  username,
-			Caption:   caption,
-		})
+		child := post
+		child.URL = url
+		child.IsVideo = isVideo
+		child.CarouselIndex = i + 1
+		if id := mediaID(media.PK); id != "" {
+			child.MediaID = id
+		}
+		if alt := strings.TrimSpace(media.AccessibilityCaption); alt != "" {
+			child.AccessibilityCaption = alt
+		}
+		if tagged := media.Usertags.usernames(); len(tagged) > 0 {
+			child.TaggedUsers = tagged
+		}
+		child.Width, child.Height = imageSize(media.ImageVersions.Candidates, media.OriginalWidth, media.OriginalHeight)
+		if isVideo {
+			child.VideoDuration = media.VideoDuration
+			applyBestVideo(&child, media.VideoVersions, media.OriginalWidth, media.OriginalHeight)
+		}
+		items = append(items, child)
 	}
 	return items
 }
 
+// applyBestVideo sets the largest video rendition on media, falling back to
+// the original dimensions when the versions carry none. URL stays the
+// thumbnail so the inline renderer keeps working for videos.
+func applyBestVideo(media *MediaItem, versions []videoVersion, width, height int) {
+	best := videoVersion{}
+	for _, version := range versions {
+		if strings.TrimSpace(version.URL) == "" {
+			continue
+		}
+		if best.URL == "" || version.Width*version.Height > best.Width*best.Height {
+			best = version
+		}
+	}
+	media.VideoURL = strings.TrimSpace(best.URL)
+	media.Width = best.Width
+	media.Height = best.Height
+	if media.Width <= 0 || media.Height <= 0 {
+		media.Width = width
+		media.Height = height
+	}
+}
+
+// imageSize prefers the original dimensions and falls back to the largest
+// rendition's.
+func imageSize(candidates []imageCandidate, width, height int) (int, int) {
+	if width > 0 && height > 0 {
+		return width, height
+	}
+	best := imageCandidate{}
+	for _, candidate := range candidates {
+		if candidate.Width*candidate.Height > best.Width*best.Height {
+			best = candidate
+		}
+	}
+	return best.Width, best.Height
+}
+
 func pickBestCandidate(candidates []imageCandidate) string {
 	if len(candidates) == 0 {
 		return ""
@@ -465,8 +794,39 @@ func doJSONRequestWithLimit(
 	if err != nil {
 		return nil, 0, err
 	}
+	return doRequestWithLimit(req, username, cookies, limit)
+}
+
+// doFormRequestWithLimit POSTs form to endpoint, for the endpoints the web app
+// only serves as form submissions.
+func doFormRequestWithLimit(
+	ctx context.Context,
+	endpoint string,
+	form url.Values,
+	username string,
+	cookies CookieBundle,
+	limit int64,
+) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doRequestWithLimit(req, username, cookies, limit)
+}
+
+func doRequestWithLimit(
+	req *http.Request,
+	username string,
+	cookies CookieBundle,
+	limit int64,
+) ([]byte, int, error) {
 	applyHeaders(req, username, cookies)
+	return sendWithLimit(req, limit)
+}
 
+// sendWithLimit performs a request whose headers are already set.
+func sendWithLimit(req *http.Request, limit int64) ([]byte, int, error) {
 	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -1,6 +1,10 @@
 package instagram
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
 
 func TestItemCaption(t *testing.T) {
 	item := feedItem{
@@ -77,6 +81,33 @@ func TestFeedItemToMediaVideo(t *testing.T) {
 	}
 }
 
+func TestFeedItemToMediaVideoVersions(t *testing.T) {
+	item := feedItem{
+		MediaType:     2,
+		ThumbnailURL:  "thumb",
+		Code:          "reel",
+		VideoDuration: 12.5,
+		VideoVersions: []videoVersion{
+			{URL: "low.mp4", Width: 480, Height: 854},
+			{URL: "high.mp4", Width: 720, Height: 1280},
+		},
+	}
+	items := feedItemToMedia(item)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	got := items[0]
+	if got.URL != "thumb" {
+		t.Fatalf("expected thumbnail to stay the url, got %q", got.URL)
+	}
+	if got.VideoURL != "high.mp4" || got.DownloadURL() != "high.mp4" {
+		t.Fatalf("expected best video url, got %q / %q", got.VideoURL, got.DownloadURL())
+	}
+	if got.Width != 720 || got.Height != 1280 || got.VideoDuration != 12.5 {
+		t.Fatalf("unexpected video metadata: %dx%d %.1fs", got.Width, got.Height, got.VideoDuration)
+	}
+}
+
 func TestFeedItemToMediaCarousel(t *testing.T) {
 	item := feedItem{
 		MediaType: 8,
@@ -86,8 +117,11 @@ func TestFeedItemToMediaCarousel(t *testing.T) {
 				ImageVersions: imageVersions{Candidates: []imageCandidate{{URL: "c1", Width: 5, Height: 5}}},
 			},
 			{
-				MediaType:    2,
-				ThumbnailURL: "c2",
+				MediaType:      2,
+				ThumbnailURL:   "c2",
+				VideoVersions:  []videoVersion{{URL: "c2.mp4"}},
+				OriginalWidth:  1080,
+				OriginalHeight: 1920,
 			},
 		},
 		Code:    "car",
@@ -101,6 +135,15 @@ func TestFeedItemToMediaCarousel(t *testing.T) {
 	if items[0].URL != "c1" || items[1].URL != "c2" {
 		t.Fatalf("unexpected urls: %q %q", items[0].URL, items[1].URL)
 	}
+	if items[0].VideoURL != "" || items[1].VideoURL != "c2.mp4" {
+		t.Fatalf("unexpected video urls: %q %q", items[0].VideoURL, items[1].VideoURL)
+	}
+	if items[0].CarouselIndex != 1 || items[1].CarouselIndex != 2 {
+		t.Fatalf("unexpected carousel indexes: %d %d", items[0].CarouselIndex, items[1].CarouselIndex)
+	}
+	if items[1].Width != 1080 || items[1].Height != 1920 {
+		t.Fatalf("expected original dimensions fallback, got %dx%d", items[1].Width, items[1].Height)
+	}
 	for _, got := range items {
 		if got.Shortcode != "car" {
 			t.Fatalf("expected shortcode car, got %q", got.Shortcode)
@@ -111,6 +154,55 @@ func TestFeedItemToMediaCarousel(t *testing.T) {
 	}
 }
 
+func TestFeedItemToMediaMetadata(t *testing.T) {
+	body := []byte(`{
+		"pk": "3300_42", "code": "M1", "media_type": 8, "product_type": "carousel_container",
+		"like_count": 120, "comment_count": 9, "lat": 48.2, "lng": 16.37,
+		"location": {"pk": 7, "name": "Vienna"},
+		"user": {"username": "owner"},
+		"coauthor_producers": [{"username": "owner"}, {"username": "collab"}],
+		"usertags": {"in": [{"user": {"username": "friend"}}]},
+		"accessibility_caption": "post alt",
+		"carousel_media": [
+			{"pk": 3301, "media_type": 1, "accessibility_caption": "first alt",
+				"image_versions2": {"candidates": [{"url": "c1", "width": 1080, "height": 1350}]}},
+			{"pk": 3302, "media_type": 1, "original_width": 640, "original_height": 640,
+				"usertags": {"in": [{"user": {"username": "other"}}]},
+				"image_versions2": {"candidates": [{"url": "c2", "width": 320, "height": 320}]}}
+		]
+	}`)
+	var item feedItem
+	if err := json.Unmarshal(body, &item); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	items := feedItemToMedia(item)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	first, second := items[0], items[1]
+	if first.MediaID != "3301" || second.MediaID != "3302" {
+		t.Fatalf("expected child media ids, got %q %q", first.MediaID, second.MediaID)
+	}
+	if first.LikeCount != 120 || first.CommentCount != 9 || first.ProductType != "carousel_container" {
+		t.Fatalf("unexpected engagement: %+v", first)
+	}
+	if first.Location == nil || first.Location.Name != "Vienna" || first.Location.ID != "7" || first.Location.Lat != 48.2 {
+		t.Fatalf("unexpected location: %+v", first.Location)
+	}
+	if len(first.Coauthors) != 1 || first.Coauthors[0] != "collab" {
+		t.Fatalf("expected collab coauthor, got %v", first.Coauthors)
+	}
+	if first.AccessibilityCaption != "first alt" || second.AccessibilityCaption != "post alt" {
+		t.Fatalf("unexpected alt text: %q %q", first.AccessibilityCaption, second.AccessibilityCaption)
+	}
+	if len(first.TaggedUsers) != 1 || first.TaggedUsers[0] != "friend" || second.TaggedUsers[0] != "other" {
+		t.Fatalf("unexpected tagged users: %v %v", first.TaggedUsers, second.TaggedUsers)
+	}
+	if first.Width != 1080 || first.Height != 1350 || second.Width != 640 {
+		t.Fatalf("unexpected image sizes: %dx%d %dx%d", first.Width, first.Height, second.Width, second.Height)
+	}
+}
+
 func TestPickBestCandidate(t *testing.T) {
 	candidates := []imageCandidate{
 		{URL: "a", Width: 10, Height: 10},
@@ -121,3 +213,150 @@ func TestPickBestCandidate(t *testing.T) {
 		t.Fatalf("expected best candidate c, got %q", got)
 	}
 }
+
+func TestDecodeFeedPage(t *testing.T) {
+	body := []byte(`{
+		"items": [
+			{"media_type": 1, "code": "L1", "user": {"username": "author"}, "caption": {"text": "liked"},
+			 "image_versions2": {"candidates": [{"url": "img"}]}}
+		],
+		"more_available": true,
+		"next_max_id": "next"
+	}`)
+	page, err := decodeFeedPage(body)
+	if err != nil {
+		t.Fatalf("decodeFeedPage: %v", err)
+	}
+	if !page.moreAvailable || page.nextMaxID != "next" || len(page.items) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	if page.items[0].Username != "author" || page.items[0].Shortcode != "L1" {
+		t.Fatalf("unexpected item: %+v", page.items[0])
+	}
+
+	wrapped := []byte(`{"feed_items": [{"media_or_ad": {"media_type": 1, "code": "H1",
+		"image_versions2": {"candidates": [{"url": "img2"}]}}}]}`)
+	page, err = decodeFeedPage(wrapped)
+	if err != nil {
+		t.Fatalf("decodeFeedPage wrapped: %v", err)
+	}
+	if len(page.items) != 1 || page.items[0].Shortcode != "H1" {
+		t.Fatalf("expected wrapped timeline entry, got %+v", page.items)
+	}
+}
+
+func TestLimitApply(t *testing.T) {
+	media := []MediaItem{
+		{URL: "a1", Shortcode: "A", CarouselIndex: 1},
+		{URL: "a2", Shortcode: "A", CarouselIndex: 2},
+		{URL: "b1", Shortcode: "B"},
+		{URL: "c1", Shortcode: "C", CarouselIndex: 1},
+		{URL: "c2", Shortcode: "C", CarouselIndex: 2},
+	}
+	got, reached := Limit{Posts: 2}.Apply(media)
+	if !reached || len(got) != 3 || got[2].URL != "b1" {
+		t.Fatalf("expected two whole posts, got %v (%v)", got, reached)
+	}
+	got, reached = Limit{Items: 2, Posts: 2}.Apply(media)
+	if !reached || len(got) != 2 {
+		t.Fatalf("expected item cap to win, got %d (%v)", len(got), reached)
+	}
+	got, reached = Limit{Posts: 5}.Apply(media)
+	if reached || len(got) != 5 {
+		t.Fatalf("expected no cut, got %d (%v)", len(got), reached)
+	}
+	if got, reached := (Limit{}).Apply(media); reached || len(got) != 5 {
+		t.Fatalf("expected zero limit to keep everything, got %d (%v)", len(got), reached)
+	}
+}
+
+func TestFeedStreamWindow(t *testing.T) {
+	feed := &fakeFeed{pages: map[string]feedPage{
+		"":   {items: []MediaItem{post("old-pinned", 5), post("e", 50), post("d", 40)}, moreAvailable: true, nextMaxID: "p2"},
+		"p2": {items: []MediaItem{post("c", 30), post("b", 20)}, moreAvailable: true, nextMaxID: "p3"},
+		"p3": {items: []MediaItem{post("a", 10)}},
+	}}
+	feed.pages[""].items[0].Pinned = true
+
+	var got []string
+	stream := newFeedStream(Limit{Since: 30, Until: 50}, func(item MediaItem) error {
+		got = append(got, item.Shortcode)
+		return nil
+	})
+	stream.chronological = true
+	if err := stream.run(context.Background(), feed.fetch); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(got) != 2 || got[0] != "d" || got[1] != "c" {
+		t.Fatalf("expected d and c, got %v", got)
+	}
+	if len(feed.calls) != 2 {
+		t.Fatalf("expected paging to stop before p3, got %v", feed.calls)
+	}
+
+	media, _ := Limit{Since: 30}.Apply([]MediaItem{post("b", 20), post("c", 30), {URL: "avatar"}})
+	if len(media) != 2 || media[0].Shortcode != "c" || media[1].URL != "avatar" {
+		t.Fatalf("unexpected Apply window: %v", media)
+	}
+}
+
+func TestStreamUserMediaExpandsProfileCarousels(t *testing.T) {
+	// The profile page only has the carousel's cover; the feed page expands it.
+	profile := Profile{
+		Username: "alice",
+		UserID:   "1",
+		Media:    []MediaItem{{URL: "cover-a", Shortcode: "A", TakenAt: 30}, post("b", 20)},
+	}
+	page, err := decodeFeedPage([]byte(`{
+		"items": [
+			{"media_type": 8, "code": "A", "taken_at": 30, "carousel_media": [
+				{"media_type": 1, "image_versions2": {"candidates": [{"url": "a1"}]}},
+				{"media_type": 1, "image_versions2": {"candidates": [{"url": "a2"}]}}
+			]},
+			{"media_type": 1, "code": "b", "taken_at": 20, "image_versions2": {"candidates": [{"url": "u-b"}]}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("decodeFeedPage: %v", err)
+	}
+	feed := &fakeFeed{pages: map[string]feedPage{"": page}}
+
+	var got []MediaItem
+	err = streamUserMedia(context.Background(), "alice", profile, Limit{Posts: 1}, feed.fetch, func(item MediaItem) error {
+		got = append(got, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streamUserMedia: %v", err)
+	}
+	if len(got) != 2 || got[0].URL != "a1" || got[0].CarouselIndex != 1 || got[1].URL != "a2" || got[1].CarouselIndex != 2 {
+		t.Fatalf("expected both slides of A, got %+v", got)
+	}
+	if got[0].Username != "alice" {
+		t.Fatalf("expected username filled in, got %q", got[0].Username)
+	}
+
+	// Without a working feed the profile page's posts stand in.
+	failing := &fakeFeed{fail: map[string]bool{"": true}}
+	got = nil
+	err = streamUserMedia(context.Background(), "alice", profile, Limit{}, failing.fetch, func(item MediaItem) error {
+		got = append(got, item)
+		return nil
+	})
+	if err == nil || len(got) != 2 || got[0].URL != "cover-a" {
+		t.Fatalf("expected profile fallback with the error, got %v (%v)", got, err)
+	}
+}
+
+func TestLimitApplyItemsKeepsAvatar(t *testing.T) {
+	items := []Item{
+		{Kind: KindAvatar, MediaItem: MediaItem{URL: "avatar"}},
+		{Kind: KindMedia, MediaItem: post("a", 30)},
+		{Kind: KindMedia, MediaItem: post("b", 20)},
+		{Kind: KindMedia, MediaItem: post("c", 10)},
+	}
+	got := Limit{Posts: 2, Match: func(m MediaItem) bool { return m.Shortcode != "" }}.ApplyItems(items)
+	if len(got) != 3 || got[0].Kind != KindAvatar || got[1].Shortcode != "a" || got[2].Shortcode != "b" {
+		t.Fatalf("expected avatar plus two posts, got %+v", got)
+	}
+}
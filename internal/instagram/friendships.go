@@ -0,0 +1,130 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	FriendshipFollowers = "followers"
+	FriendshipFollowing = "following"
+)
+
+// Account is a user as listed in follower and following lists.
+type Account struct {
+	ID            string
+	Username      string
+	FullName      string
+	IsPrivate     bool
+	IsVerified    bool
+	ProfilePicURL string
+}
+
+type friendshipsResponse struct {
+	Users     []friendshipUser `json:"users"`
+	NextMaxID flexID           `json:"next_max_id"`
+	BigList   bool             `json:"big_list"`
+}
+
+type friendshipUser struct {
+	PK            flexID `json:"pk"`
+	PKID          string `json:"pk_id"`
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	IsPrivate     bool   `json:"is_private"`
+	IsVerified    bool   `json:"is_verified"`
+	ProfilePicURL string `json:"profile_pic_url"`
+}
+
+// FetchFriendships pages the followers or following list (see
+// FriendshipFollowers and FriendshipFollowing) of a user. max caps the number
+// of accounts (0 = all).
+func FetchFriendships(
+	ctx context.Context,
+	username string,
+	userID string,
+	list string,
+	cookies CookieBundle,
+	max int,
+	pageSize int,
+) ([]Account, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	if list != FriendshipFollowers && list != FriendshipFollowing {
+		return nil, fmt.Errorf("unsupported friendship list: %s", list)
+	}
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	out := make([]Account, 0, pageSize)
+	seen := map[string]struct{}{}
+	maxID := ""
+	for pageCount := 1; ; pageCount++ {
+		endpoint := fmt.Sprintf(
+			"https://www.instagram.com/api/v1/friendships/%s/%s/?count=%d",
+			url.PathEscape(userID),
+			list,
+			pageSize,
+		)
+		if maxID != "" {
+			endpoint += "&max_id=" + url.QueryEscape(maxID)
+		}
+		body, status, err := doJSONRequestWithLimit(ctx, endpoint, username, cookies, 4<<20)
+		if err != nil {
+			return out, fmt.Errorf("%s request failed (%d): %s", list, status, errText(err))
+		}
+		accounts, next, err := decodeFriendships(body)
+		if err != nil {
+			return out, err
+		}
+		for _, account := range accounts {
+			if _, ok := seen[account.ID]; ok {
+				continue
+			}
+			seen[account.ID] = struct{}{}
+			out = append(out, account)
+			if max > 0 && len(out) >= max {
+				return out, nil
+			}
+		}
+		if next == "" || next == maxID || pageCount > 200 {
+			return out, nil
+		}
+		maxID = next
+	}
+}
+
+func decodeFriendships(body []byte) ([]Account, string, error) {
+	var raw friendshipsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, "", err
+	}
+	out := make([]Account, 0, len(raw.Users))
+	for _, user := range raw.Users {
+		id := strings.TrimSpace(string(user.PK))
+		if id == "" {
+			id = strings.TrimSpace(user.PKID)
+		}
+		if id == "" {
+			continue
+		}
+		out = append(out, Account{
+			ID:            id,
+			Username:      strings.TrimSpace(user.Username),
+			FullName:      strings.TrimSpace(user.FullName),
+			IsPrivate:     user.IsPrivate,
+			IsVerified:    user.IsVerified,
+			ProfilePicURL: strings.TrimSpace(user.ProfilePicURL),
+		})
+	}
+	return out, strings.TrimSpace(string(raw.NextMaxID)), nil
+}
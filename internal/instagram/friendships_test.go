@@ -0,0 +1,30 @@
+package instagram
+
+import "testing"
+
+func TestDecodeFriendships(t *testing.T) {
+	body := []byte(`{
+		"users": [
+			{"pk": 42, "username": "alice", "full_name": " Alice A ", "is_verified": true, "profile_pic_url": "pic"},
+			{"pk_id": "43", "username": "bob", "is_private": true},
+			{"username": "no-id"}
+		],
+		"next_max_id": 100
+	}`)
+	accounts, next, err := decodeFriendships(body)
+	if err != nil {
+		t.Fatalf("decodeFriendships: %v", err)
+	}
+	if next != "100" {
+		t.Fatalf("expected numeric cursor as string, got %q", next)
+	}
+	if len(accounts) != 2 {
+		t.Fatalf("expected 2 accounts, got %d", len(accounts))
+	}
+	if accounts[0].ID != "42" || accounts[0].FullName != "Alice A" || !accounts[0].IsVerified || accounts[0].ProfilePicURL != "pic" {
+		t.Fatalf("unexpected first account: %+v", accounts[0])
+	}
+	if accounts[1].ID != "43" || !accounts[1].IsPrivate {
+		t.Fatalf("unexpected second account: %+v", accounts[1])
+	}
+}
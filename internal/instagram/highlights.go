@@ -0,0 +1,118 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Highlight is one story highlight reel pinned to a profile.
+type Highlight struct {
+	ID         string
+	Title      string
+	CoverURL   string
+	MediaCount int
+}
+
+type highlightsTrayResponse struct {
+	Tray []highlightReel `json:"tray"`
+}
+
+type highlightReel struct {
+	ID         flexID `json:"id"`
+	Title      string `json:"title"`
+	MediaCount int    `json:"media_count"`
+	CoverMedia struct {
+		CroppedImageVersion imageCandidate `json:"cropped_image_version"`
+		FullImageVersion    imageCandidate `json:"full_image_version"`
+	} `json:"cover_media"`
+}
+
+// FetchHighlights lists the highlight reels of a profile.
+func FetchHighlights(ctx context.Context, username, userID string, cookies CookieBundle) ([]Highlight, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	endpoint := fmt.Sprintf(
+		"https://www.instagram.com/api/v1/highlights/%s/highlights_tray/",
+		url.PathEscape(userID),
+	)
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, username, cookies, 4<<20)
+	if err != nil {
+		return nil, fmt.Errorf("highlights request failed (%d): %s", status, errText(err))
+	}
+	return decodeHighlights(body)
+}
+
+func decodeHighlights(body []byte) ([]Highlight, error) {
+	var raw highlightsTrayResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	out := make([]Highlight, 0, len(raw.Tray))
+	for _, reel := range raw.Tray {
+		id := strings.TrimSpace(string(reel.ID))
+		if id == "" {
+			continue
+		}
+		if !strings.HasPrefix(id, "highlight:") {
+			id = "highlight:" + id
+		}
+		cover := strings.TrimSpace(reel.CoverMedia.FullImageVersion.URL)
+		if cover == "" {
+			cover = strings.TrimSpace(reel.CoverMedia.CroppedImageVersion.URL)
+		}
+		out = append(out, Highlight{
+			ID:         id,
+			Title:      strings.TrimSpace(reel.Title),
+			CoverURL:   cover,
+			MediaCount: reel.MediaCount,
+		})
+	}
+	return out, nil
+}
+
+// FetchHighlightMedia expands highlights into their items, each tagged with
+// the title of the highlight it belongs to. Items limit rejects are dropped,
+// and no further highlights are requested once it is reached.
+func FetchHighlightMedia(ctx context.Context, username string, highlights []Highlight, cookies CookieBundle, limit Limit) ([]MediaItem, error) {
+	return collectHighlightMedia(highlights, limit, func(ids []string) ([]storyReel, error) {
+		return fetchReels(ctx, ids, username, cookies)
+	})
+}
+
+func collectHighlightMedia(highlights []Highlight, limit Limit, fetch func(ids []string) ([]storyReel, error)) ([]MediaItem, error) {
+	titles := make(map[string]string, len(highlights))
+	ids := make([]string, 0, len(highlights))
+	for _, highlight := range highlights {
+		titles[highlight.ID] = highlight.Title
+		ids = append(ids, highlight.ID)
+	}
+
+	out := make([]MediaItem, 0)
+	for start := 0; start < len(ids); start += reelsMediaBatch {
+		end := start + reelsMediaBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		reels, err := fetch(ids[start:end])
+		if err != nil {
+			return out, err
+		}
+		for _, reel := range reels {
+			title := titles[string(reel.ID)]
+			for _, media := range reelMedia(reel) {
+				media.Highlight = title
+				out = append(out, media)
+			}
+		}
+		var reached bool
+		if out, reached = limit.Apply(out); reached {
+			break
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,70 @@
+package instagram
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDecodeHighlights(t *testing.T) {
+	body := []byte(`{
+		"tray": [
+			{"id": "highlight:111", "title": " Travel ", "media_count": 5,
+			 "cover_media": {"cropped_image_version": {"url": "crop"}, "full_image_version": {"url": "full"}}},
+			{"id": 222, "title": "Food", "media_count": 2,
+			 "cover_media": {"cropped_image_version": {"url": "crop2"}}},
+			{"title": "no id"}
+		]
+	}`)
+	highlights, err := decodeHighlights(body)
+	if err != nil {
+		t.Fatalf("decodeHighlights: %v", err)
+	}
+	if len(highlights) != 2 {
+		t.Fatalf("expected 2 highlights, got %d", len(highlights))
+	}
+	first := highlights[0]
+	if first.ID != "highlight:111" || first.Title != "Travel" || first.CoverURL != "full" || first.MediaCount != 5 {
+		t.Fatalf("unexpected first highlight: %+v", first)
+	}
+	second := highlights[1]
+	if second.ID != "highlight:222" || second.CoverURL != "crop2" {
+		t.Fatalf("unexpected second highlight: %+v", second)
+	}
+}
+
+func TestCollectHighlightMediaStopsAtLimit(t *testing.T) {
+	var highlights []Highlight
+	for i := 0; i < reelsMediaBatch+1; i++ {
+		highlights = append(highlights, Highlight{ID: fmt.Sprintf("highlight:%d", i), Title: fmt.Sprintf("h%d", i)})
+	}
+	calls := 0
+	fetch := func(ids []string) ([]storyReel, error) {
+		calls++
+		reels := make([]storyReel, 0, len(ids))
+		for _, id := range ids {
+			reels = append(reels, storyReel{ID: flexID(id), Items: []feedItem{
+				{MediaType: 2, ImageVersions: imageVersions{Candidates: []imageCandidate{{URL: id + "-video"}}},
+					VideoVersions: []videoVersion{{URL: id + ".mp4"}}},
+				{MediaType: 1, ImageVersions: imageVersions{Candidates: []imageCandidate{{URL: id + "-photo"}}}},
+			}})
+		}
+		return reels, nil
+	}
+
+	photos := Limit{Items: 2, Match: func(item MediaItem) bool { return !item.IsVideo }}
+	media, err := collectHighlightMedia(highlights, photos, fetch)
+	if err != nil {
+		t.Fatalf("collectHighlightMedia: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one reels request, got %d", calls)
+	}
+	if len(media) != 2 || media[0].URL != "highlight:0-photo" || media[0].Highlight != "h0" || media[1].URL != "highlight:1-photo" {
+		t.Fatalf("unexpected media: %+v", media)
+	}
+
+	calls = 0
+	if media, _ = collectHighlightMedia(highlights, Limit{}, fetch); calls != 2 || len(media) != 2*len(highlights) {
+		t.Fatalf("expected every highlight without a limit, got %d items in %d requests", len(media), calls)
+	}
+}
@@ -0,0 +1,30 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const likedFeedURL = "https://www.instagram.com/api/v1/feed/liked/"
+
+// FetchLikedMedia returns the posts the logged-in user liked, most recent
+// like first, credited to their original authors.
+func FetchLikedMedia(ctx context.Context, cookies CookieBundle, limit Limit) ([]MediaItem, error) {
+	return collectFeed(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchLikedPage(ctx, maxID, cookies)
+	}, limit)
+}
+
+func fetchLikedPage(ctx context.Context, maxID string, cookies CookieBundle) (feedPage, error) {
+	endpoint := likedFeedURL
+	if strings.TrimSpace(maxID) != "" {
+		endpoint += "?max_id=" + url.QueryEscape(maxID)
+	}
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 4<<20)
+	if err != nil {
+		return feedPage{}, fmt.Errorf("liked posts request failed (%d): %s", status, errText(err))
+	}
+	return decodeFeedPage(body)
+}
@@ -0,0 +1,77 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// FetchPost looks up a single post by shortcode and returns its files,
+// one per carousel child.
+func FetchPost(ctx context.Context, shortcode string, cookies CookieBundle) ([]MediaItem, error) {
+	mediaID, err := ShortcodeToMediaID(shortcode)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("https://www.instagram.com/api/v1/media/%s/info/", url.PathEscape(mediaID))
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 4<<20)
+	if err != nil {
+		return nil, fmt.Errorf("post request failed (%d): %s", status, errText(err))
+	}
+	page, err := decodeFeedPage(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.items) == 0 {
+		return nil, fmt.Errorf("post %s not found", strings.TrimSpace(shortcode))
+	}
+	return page.items, nil
+}
+
+// Post groups the files of one post in carousel order.
+type Post struct {
+	Shortcode string
+	Username  string
+	TakenAt   int64
+	Caption   string
+	Children  []MediaItem
+}
+
+// PostKey identifies the post an item belongs to: its shortcode, or the URL
+// for items without one, which stand alone.
+func PostKey(media MediaItem) string {
+	if code := strings.TrimSpace(media.Shortcode); code != "" {
+		return "p/" + code
+	}
+	return "u/" + media.URL
+}
+
+// GroupPosts regroups flattened media into posts, in order of first
+// appearance. Carousel children are sorted by CarouselIndex.
+func GroupPosts(media []MediaItem) []Post {
+	posts := make([]Post, 0, len(media))
+	index := map[string]int{}
+	for _, item := range media {
+		key := PostKey(item)
+		i, ok := index[key]
+		if !ok {
+			i = len(posts)
+			index[key] = i
+			posts = append(posts, Post{
+				Shortcode: strings.TrimSpace(item.Shortcode),
+				Username:  item.Username,
+				TakenAt:   item.TakenAt,
+				Caption:   item.Caption,
+			})
+		}
+		posts[i].Children = append(posts[i].Children, item)
+	}
+	for _, post := range posts {
+		sort.SliceStable(post.Children, func(a, b int) bool {
+			return post.Children[a].CarouselIndex < post.Children[b].CarouselIndex
+		})
+	}
+	return posts
+}
@@ -0,0 +1,25 @@
+package instagram
+
+import "testing"
+
+func TestGroupPosts(t *testing.T) {
+	posts := GroupPosts([]MediaItem{
+		{URL: "a2", Shortcode: "A", Username: "u", CarouselIndex: 2},
+		{URL: "b1", Shortcode: "B"},
+		{URL: "a1", Shortcode: "A", Username: "u", CarouselIndex: 1},
+		{URL: "avatar"},
+	})
+	if len(posts) != 3 {
+		t.Fatalf("expected 3 posts, got %d", len(posts))
+	}
+	first := posts[0]
+	if first.Shortcode != "A" || first.Username != "u" || len(first.Children) != 2 {
+		t.Fatalf("unexpected first post: %+v", first)
+	}
+	if first.Children[0].URL != "a1" || first.Children[1].URL != "a2" {
+		t.Fatalf("expected carousel order, got %q, %q", first.Children[0].URL, first.Children[1].URL)
+	}
+	if posts[2].Shortcode != "" || posts[2].Children[0].URL != "avatar" {
+		t.Fatalf("expected item without shortcode to stand alone: %+v", posts[2])
+	}
+}
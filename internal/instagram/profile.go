@@ -20,12 +20,53 @@ type Profile struct {
 }
 
 type MediaItem struct {
-	URL       string
-	IsVideo   bool
-	Shortcode string
-	TakenAt   int64
-	Username  string
-	Caption   string
+	URL           string
+	IsVideo       bool
+	Shortcode     string
+	TakenAt       int64
+	Username      string
+	Caption       string
+	Pinned        bool
+	VideoURL      string
+	VideoDuration float64
+	Width         int
+	Height        int
+	ExpiringAt    int64
+	Highlight     string
+	PlayCount     int64
+	ViewCount     int64
+	AudioTitle    string
+	AudioArtist   string
+	// TaggedAccount is the account whose tagged tab listed the post.
+	TaggedAccount        string
+	MediaID              string
+	LikeCount            int64
+	CommentCount         int64
+	Location             *Location
+	AccessibilityCaption string
+	// TaggedUsers are the accounts tagged in the photo itself.
+	TaggedUsers   []string
+	Coauthors     []string
+	ProductType   string
+	CarouselIndex int
+}
+
+// Location is the place a post is tagged with. Lat and Lng are zero when the
+// API omits coordinates.
+type Location struct {
+	ID   string
+	Name string
+	Lat  float64
+	Lng  float64
+}
+
+// DownloadURL is the full-resolution file for the item: the MP4 for videos
+// when one is known, otherwise the image URL.
+func (m MediaItem) DownloadURL() string {
+	if m.IsVideo && strings.TrimSpace(m.VideoURL) != "" {
+		return m.VideoURL
+	}
+	return m.URL
 }
 
 const (
@@ -85,11 +126,44 @@ type mediaEdge struct {
 }
 
 type mediaNode struct {
-	DisplayURL       string `json:"display_url"`
-	ThumbnailSrc     string `json:"thumbnail_src"`
-	IsVideo          bool   `json:"is_video"`
-	Shortcode        string `json:"shortcode"`
-	TakenAtTimestamp int64  `json:"taken_at_timestamp"`
+	DisplayURL       string  `json:"display_url"`
+	ThumbnailSrc     string  `json:"thumbnail_src"`
+	IsVideo          bool    `json:"is_video"`
+	Shortcode        string  `json:"shortcode"`
+	TakenAtTimestamp int64   `json:"taken_at_timestamp"`
+	VideoURL         string  `json:"video_url"`
+	VideoDuration    float64 `json:"video_duration"`
+	Dimensions       struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"dimensions"`
+	ID                   string     `json:"id"`
+	ProductType          string     `json:"product_type"`
+	AccessibilityCaption string     `json:"accessibility_caption"`
+	VideoViewCount       int64      `json:"video_view_count"`
+	EdgeLikedBy          edgeCount  `json:"edge_liked_by"`
+	EdgeMediaPreviewLike edgeCount  `json:"edge_media_preview_like"`
+	EdgeMediaToComment   edgeCount  `json:"edge_media_to_comment"`
+	Location             *nodePlace `json:"location"`
+	PinnedForUsers       []struct {
+		ID string `json:"id"`
+	} `json:"pinned_for_users"`
+	EdgeMediaToCaption struct {
+		Edges []struct {
+			Node struct {
+				Text string `json:"text"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_media_to_caption"`
+}
+
+type edgeCount struct {
+	Count int64 `json:"count"`
+}
+
+type nodePlace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 func fetchProfilePayload(
@@ -213,13 +287,37 @@ func buildProfile(user *profileUser) Profile {
 		if url == "" {
 			continue
 		}
-		profile.Media = append(profile.Media, MediaItem{
-			URL:       url,
-			IsVideo:   node.IsVideo,
-			Shortcode: node.Shortcode,
-			TakenAt:   node.TakenAtTimestamp,
-			Username:  user.Username,
-		})
+		likes := node.EdgeLikedBy.Count
+		if likes == 0 {
+			likes = node.EdgeMediaPreviewLike.Count
+		}
+		media := MediaItem{
+			URL:                  url,
+			IsVideo:              node.IsVideo,
+			Shortcode:            node.Shortcode,
+			TakenAt:              node.TakenAtTimestamp,
+			Username:             user.Username,
+			Pinned:               len(node.PinnedForUsers) > 0,
+			MediaID:              strings.TrimSpace(node.ID),
+			LikeCount:            likes,
+			CommentCount:         node.EdgeMediaToComment.Count,
+			ViewCount:            node.VideoViewCount,
+			AccessibilityCaption: strings.TrimSpace(node.AccessibilityCaption),
+			ProductType:          node.ProductType,
+			Width:                node.Dimensions.Width,
+			Height:               node.Dimensions.Height,
+		}
+		if edges := node.EdgeMediaToCaption.Edges; len(edges) > 0 {
+			media.Caption = strings.TrimSpace(edges[0].Node.Text)
+		}
+		if place := node.Location; place != nil && place.Name != "" {
+			media.Location = &Location{ID: place.ID, Name: place.Name}
+		}
+		if node.IsVideo {
+			media.VideoURL = strings.TrimSpace(node.VideoURL)
+			media.VideoDuration = node.VideoDuration
+		}
+		profile.Media = append(profile.Media, media)
 	}
 
 	return profile
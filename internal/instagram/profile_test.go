@@ -0,0 +1,50 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildProfilePinnedPosts(t *testing.T) {
+	raw := `{
+		"id": "",
+		"username": "alice",
+		"edge_owner_to_timeline_media": {"edges": [
+			{"node": {"display_url": "https://x/old.jpg", "shortcode": "old", "taken_at_timestamp": 5, "pinned_for_users": [{"id": "1"}]}},
+			{"node": {"display_url": "https://x/new.jpg", "shortcode": "new", "taken_at_timestamp": 500}}
+		]}
+	}`
+	var user profileUser
+	if err := json.Unmarshal([]byte(raw), &user); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	profile := buildProfile(&user)
+	if len(profile.Media) != 2 || !profile.Media[0].Pinned || profile.Media[1].Pinned {
+		t.Fatalf("expected only the first post pinned, got %+v", profile.Media)
+	}
+
+	var got []string
+	err := StreamUserMedia(context.Background(), "alice", profile, CookieBundle{}, Limit{Since: 100}, 50, func(item MediaItem) error {
+		got = append(got, item.Shortcode)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamUserMedia: %v", err)
+	}
+	if len(got) != 1 || got[0] != "new" {
+		t.Fatalf("expected the newer post past the old pinned one, got %v", got)
+	}
+
+	// Without the pinned marker the profile batch must not end the stream
+	// either.
+	profile.Media[0].Pinned = false
+	got = nil
+	_ = StreamUserMedia(context.Background(), "alice", profile, CookieBundle{}, Limit{Since: 100}, 50, func(item MediaItem) error {
+		got = append(got, item.Shortcode)
+		return nil
+	})
+	if len(got) != 1 || got[0] != "new" {
+		t.Fatalf("expected the newer post without a pinned marker, got %v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const clipsUserURL = "https://www.instagram.com/api/v1/clips/user/"
+
+type clipsResponse struct {
+	Items      []clipsEntry `json:"items"`
+	PagingInfo struct {
+		MaxID         string `json:"max_id"`
+		MoreAvailable bool   `json:"more_available"`
+	} `json:"paging_info"`
+}
+
+type clipsEntry struct {
+	Media feedItem `json:"media"`
+}
+
+// FetchUserReels pages the reels tab of an account. Unlike the grid feed it
+// includes reels-only posts and carries play counts and audio.
+func FetchUserReels(
+	ctx context.Context,
+	username string,
+	userID string,
+	cookies CookieBundle,
+	limit Limit,
+	pageSize int,
+) ([]MediaItem, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	if pageSize <= 0 {
+		pageSize = 12
+	}
+	if pageSize > 50 {
+		pageSize = 50
+	}
+
+	return collectReels(ctx, username, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchUserReelsPage(ctx, username, userID, maxID, pageSize, cookies)
+	}, limit)
+}
+
+// collectReels pages the reels tab of username. The tab lists pinned reels
+// first and the rest newest first, so it ends at the first unpinned reel
+// older than limit.Since, as the grid feed does.
+func collectReels(ctx context.Context, username string, fetch pageFetcher, limit Limit) ([]MediaItem, error) {
+	out := make([]MediaItem, 0)
+	stream := newFeedStream(limit, func(item MediaItem) error {
+		out = append(out, item)
+		return nil
+	})
+	stream.chronological = true
+	err := stream.run(ctx, fetch)
+	for i := range out {
+		if strings.TrimSpace(out[i].Username) == "" {
+			out[i].Username = username
+		}
+	}
+	return out, err
+}
+
+func fetchUserReelsPage(
+	ctx context.Context,
+	username string,
+	userID string,
+	maxID string,
+	pageSize int,
+	cookies CookieBundle,
+) (feedPage, error) {
+	form := url.Values{}
+	form.Set("target_user_id", userID)
+	form.Set("page_size", strconv.Itoa(pageSize))
+	form.Set("include_feed_video", "true")
+	if strings.TrimSpace(maxID) != "" {
+		form.Set("max_id", maxID)
+	}
+
+	body, status, err := doFormRequestWithLimit(ctx, clipsUserURL, form, username, cookies, 4<<20)
+	if err != nil {
+		return feedPage{}, fmt.Errorf("reels request failed (%d): %s", status, errText(err))
+	}
+	return decodeClipsPage(body)
+}
+
+func decodeClipsPage(body []byte) (feedPage, error) {
+	var raw clipsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return feedPage{}, err
+	}
+	items := make([]MediaItem, 0, len(raw.Items))
+	for _, entry := range raw.Items {
+		for _, item := range feedItemToMedia(entry.Media) {
+			item.Pinned = item.Pinned || len(entry.Media.ClipsTabPinnedUserIDs) > 0
+			items = append(items, item)
+		}
+	}
+	return feedPage{
+		items:         items,
+		moreAvailable: raw.PagingInfo.MoreAvailable,
+		nextMaxID:     raw.PagingInfo.MaxID,
+	}, nil
+}
+
+// MergeReels folds reels into grid media. Reels already on the grid share a
+// shortcode with a grid post; those only lend it their counts and audio.
+// Reels-only posts are merged in by TakenAt, newest first, each before the
+// first unpinned grid item older than it, so pinned grid posts stay on top.
+func MergeReels(grid []MediaItem, reels []MediaItem) []MediaItem {
+	byShortcode := make(map[string]MediaItem, len(reels))
+	for _, reel := range reels {
+		if code := strings.TrimSpace(reel.Shortcode); code != "" {
+			if _, ok := byShortcode[code]; !ok {
+				byShortcode[code] = reel
+			}
+		}
+	}
+
+	out := make([]MediaItem, 0, len(grid)+len(reels))
+	onGrid := map[string]struct{}{}
+	for _, item := range grid {
+		code := strings.TrimSpace(item.Shortcode)
+		if reel, ok := byShortcode[code]; ok && code != "" {
+			onGrid[code] = struct{}{}
+			if item.PlayCount == 0 {
+				item.PlayCount = reel.PlayCount
+			}
+			if item.ViewCount == 0 {
+				item.ViewCount = reel.ViewCount
+			}
+			if item.AudioTitle == "" {
+				item.AudioTitle = reel.AudioTitle
+				item.AudioArtist = reel.AudioArtist
+			}
+			if item.LikeCount == 0 {
+				item.LikeCount = reel.LikeCount
+			}
+			if item.CommentCount == 0 {
+				item.CommentCount = reel.CommentCount
+			}
+		}
+		out = append(out, item)
+	}
+	var extra []MediaItem
+	for _, reel := range reels {
+		if _, ok := onGrid[strings.TrimSpace(reel.Shortcode)]; ok {
+			continue
+		}
+		extra = append(extra, reel)
+	}
+	sort.SliceStable(extra, func(i, j int) bool { return extra[i].TakenAt > extra[j].TakenAt })
+
+	merged := make([]MediaItem, 0, len(out)+len(extra))
+	next := 0
+	for _, item := range out {
+		if !item.Pinned {
+			for next < len(extra) && extra[next].TakenAt > item.TakenAt {
+				merged = append(merged, extra[next])
+				next++
+			}
+		}
+		merged = append(merged, item)
+	}
+	out = append(merged, extra[next:]...)
+	return out
+}
+
+// ReelsLimit is the limit for the reels merged into grid, a capped crawl of
+// limit. The merged list is cut at limit afterwards, so when grid reached
+// the cap, reels older than its oldest unpinned item cannot make the cut and
+// the reels crawl stops there. The grid's own reels are among the newest
+// reels, so it needs at most one reel per grid post plus the cap itself.
+func ReelsLimit(limit Limit, grid []MediaItem) Limit {
+	budget := max(limit.Items, limit.Posts)
+	if budget <= 0 {
+		return limit
+	}
+	posts := len(GroupPosts(grid))
+	reels := limit
+	reels.Items = 0
+	reels.Posts = posts + budget
+	full := (limit.Items > 0 && len(grid) >= limit.Items) || (limit.Posts > 0 && posts >= limit.Posts)
+	if !full {
+		return reels
+	}
+	var oldest int64
+	for _, item := range grid {
+		if !item.Pinned && item.TakenAt > 0 && (oldest == 0 || item.TakenAt < oldest) {
+			oldest = item.TakenAt
+		}
+	}
+	reels.Since = max(reels.Since, oldest)
+	return reels
+}
@@ -0,0 +1,115 @@
+package instagram
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDecodeClipsPage(t *testing.T) {
+	body := []byte(`{
+		"items": [
+			{"media": {
+				"media_type": 2, "code": "R1", "taken_at": 50, "play_count": 0, "ig_play_count": 1200, "view_count": 900,
+				"image_versions2": {"candidates": [{"url": "thumb", "width": 640, "height": 1136}]},
+				"video_versions": [{"url": "mp4", "width": 720, "height": 1280}],
+				"clips_metadata": {"music_info": {"music_asset_info": {"title": "Song", "display_artist": "Band"}}}
+			}},
+			{"media": {
+				"media_type": 2, "code": "R2", "play_count": 7, "clips_tab_pinned_user_ids": [1],
+				"image_versions2": {"candidates": [{"url": "thumb2"}]},
+				"clips_metadata": {"original_sound_info": {"original_audio_title": "Original audio", "ig_artist": {"username": "tester"}}}
+			}}
+		],
+		"paging_info": {"max_id": "next", "more_available": true}
+	}`)
+	page, err := decodeClipsPage(body)
+	if err != nil {
+		t.Fatalf("decodeClipsPage: %v", err)
+	}
+	if !page.moreAvailable || page.nextMaxID != "next" {
+		t.Fatalf("unexpected paging: %+v", page)
+	}
+	if len(page.items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(page.items))
+	}
+	first := page.items[0]
+	if first.PlayCount != 1200 || first.ViewCount != 900 || first.VideoURL != "mp4" {
+		t.Fatalf("unexpected first reel: %+v", first)
+	}
+	if first.AudioTitle != "Song" || first.AudioArtist != "Band" {
+		t.Fatalf("unexpected music info: %q / %q", first.AudioTitle, first.AudioArtist)
+	}
+	second := page.items[1]
+	if first.Pinned || !second.Pinned {
+		t.Fatalf("expected only the second reel pinned: %v / %v", first.Pinned, second.Pinned)
+	}
+	if second.PlayCount != 7 || second.AudioTitle != "Original audio" || second.AudioArtist != "tester" {
+		t.Fatalf("unexpected second reel: %+v", second)
+	}
+}
+
+func TestMergeReels(t *testing.T) {
+	grid := []MediaItem{
+		{URL: "pinned", Shortcode: "P", TakenAt: 10, Pinned: true},
+		{URL: "g1", Shortcode: "A", TakenAt: 50},
+		{URL: "g2", Shortcode: "B", TakenAt: 30, IsVideo: true},
+		{URL: "g3", Shortcode: "D", TakenAt: 20},
+	}
+	reels := []MediaItem{
+		{URL: "r2", Shortcode: "B", TakenAt: 30, IsVideo: true, PlayCount: 10, AudioTitle: "Song"},
+		{URL: "r3", Shortcode: "C", TakenAt: 40, IsVideo: true, PlayCount: 3},
+		{URL: "r4", Shortcode: "E", TakenAt: 60, IsVideo: true},
+		{URL: "r5", Shortcode: "F", TakenAt: 5, IsVideo: true},
+	}
+	merged := MergeReels(grid, reels)
+	var order []string
+	for _, item := range merged {
+		order = append(order, item.Shortcode)
+	}
+	if got := strings.Join(order, ","); got != "P,E,A,C,B,D,F" {
+		t.Fatalf("expected reels-only posts merged by date below the pinned post, got %s", got)
+	}
+	if merged[4].URL != "g2" || merged[4].PlayCount != 10 || merged[4].AudioTitle != "Song" {
+		t.Fatalf("expected grid post enriched by reel, got %+v", merged[4])
+	}
+}
+
+func TestCollectReelsStopsAtSince(t *testing.T) {
+	feed := &fakeFeed{pages: map[string]feedPage{
+		"": {items: []MediaItem{
+			{URL: "old-pinned", Shortcode: "P", TakenAt: 5, Pinned: true},
+			post("A", 50),
+			post("B", 40),
+		}, moreAvailable: true, nextMaxID: "1"},
+		"1": {items: []MediaItem{post("C", 20), post("D", 10)}, moreAvailable: true, nextMaxID: "2"},
+		"2": {items: []MediaItem{post("E", 5)}},
+	}}
+	reels, err := collectReels(context.Background(), "me", feed.fetch, Limit{Since: 15})
+	if err != nil {
+		t.Fatalf("collectReels: %v", err)
+	}
+	if len(reels) != 3 || reels[0].Shortcode != "A" || reels[2].Shortcode != "C" || reels[0].Username != "me" {
+		t.Fatalf("unexpected reels: %+v", reels)
+	}
+	if len(feed.calls) != 2 {
+		t.Fatalf("expected the crawl to stop at the first reel before --since, got calls %q", feed.calls)
+	}
+}
+
+func TestReelsLimit(t *testing.T) {
+	grid := []MediaItem{
+		{URL: "pinned", Shortcode: "P", TakenAt: 1, Pinned: true},
+		post("A", 50),
+		post("B", 30),
+	}
+	if got := ReelsLimit(Limit{Posts: 3, Since: 10}, grid); got.Posts != 6 || got.Items != 0 || got.Since != 30 {
+		t.Fatalf("expected a full grid to bound the reels at its oldest post, got %+v", got)
+	}
+	if got := ReelsLimit(Limit{Posts: 5, Since: 10}, grid); got.Posts != 8 || got.Since != 10 {
+		t.Fatalf("expected a partial grid to keep the window, got %+v", got)
+	}
+	if got := ReelsLimit(Limit{Since: 10}, grid); got.Posts != 0 || got.Since != 10 {
+		t.Fatalf("expected no cap without one, got %+v", got)
+	}
+}
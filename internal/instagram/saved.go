@@ -0,0 +1,148 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	savedPostsURL      = "https://www.instagram.com/api/v1/feed/saved/posts/"
+	collectionsListURL = "https://www.instagram.com/api/v1/collections/list/"
+)
+
+// savedResponse is a page of saved posts; unlike the user feed each item wraps
+// its post in "media".
+type savedResponse struct {
+	Items         []feedEntry `json:"items"`
+	MoreAvailable bool        `json:"more_available"`
+	NextMaxID     string      `json:"next_max_id"`
+}
+
+type collectionsResponse struct {
+	Items         []collectionEntry `json:"items"`
+	MoreAvailable bool              `json:"more_available"`
+	NextMaxID     string            `json:"next_max_id"`
+}
+
+type collectionEntry struct {
+	ID         flexID `json:"collection_id"`
+	Name       string `json:"collection_name"`
+	MediaCount int    `json:"collection_media_count"`
+	Type       string `json:"collection_type"`
+}
+
+// Collection is a named group of saved posts.
+type Collection struct {
+	ID         string
+	Name       string
+	MediaCount int
+}
+
+// FetchSavedMedia returns the saved posts of the logged-in user, newest
+// save first.
+func FetchSavedMedia(ctx context.Context, cookies CookieBundle, limit Limit) ([]MediaItem, error) {
+	return collectFeed(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchSavedPage(ctx, savedPostsURL, maxID, cookies)
+	}, limit)
+}
+
+// FetchCollectionMedia returns the posts saved to one collection.
+func FetchCollectionMedia(ctx context.Context, collectionID string, cookies CookieBundle, limit Limit) ([]MediaItem, error) {
+	collectionID = strings.TrimSpace(collectionID)
+	if collectionID == "" {
+		return nil, fmt.Errorf("collection id is required")
+	}
+	endpoint := fmt.Sprintf("https://www.instagram.com/api/v1/feed/collection/%s/posts/", url.PathEscape(collectionID))
+	return collectFeed(ctx, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchSavedPage(ctx, endpoint, maxID, cookies)
+	}, limit)
+}
+
+// FetchCollections lists the saved collections of the logged-in user. The
+// automatic "All posts" collection is left out; FetchSavedMedia covers it.
+func FetchCollections(ctx context.Context, cookies CookieBundle) ([]Collection, error) {
+	out := make([]Collection, 0)
+	maxID := ""
+	for pageCount := 1; ; pageCount++ {
+		query := url.Values{}
+		query.Set("collection_types", `["MEDIA"]`)
+		if maxID != "" {
+			query.Set("max_id", maxID)
+		}
+		body, status, err := doJSONRequestWithLimit(ctx, collectionsListURL+"?"+query.Encode(), "", cookies, 2<<20)
+		if err != nil {
+			return out, fmt.Errorf("collections request failed (%d): %s", status, errText(err))
+		}
+		collections, more, next, err := decodeCollections(body)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, collections...)
+		if !more || next == "" || next == maxID || pageCount > 200 {
+			return out, nil
+		}
+		maxID = next
+	}
+}
+
+// FindCollection picks the collection whose name (case-insensitive) or id is
+// name.
+func FindCollection(collections []Collection, name string) (Collection, bool) {
+	name = strings.TrimSpace(name)
+	for _, collection := range collections {
+		if strings.EqualFold(collection.Name, name) || collection.ID == name {
+			return collection, true
+		}
+	}
+	return Collection{}, false
+}
+
+func fetchSavedPage(ctx context.Context, endpoint string, maxID string, cookies CookieBundle) (feedPage, error) {
+	if strings.TrimSpace(maxID) != "" {
+		endpoint += "?max_id=" + url.QueryEscape(maxID)
+	}
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, "", cookies, 4<<20)
+	if err != nil {
+		return feedPage{}, fmt.Errorf("saved posts request failed (%d): %s", status, errText(err))
+	}
+	return decodeSavedPage(body)
+}
+
+func decodeSavedPage(body []byte) (feedPage, error) {
+	var raw savedResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return feedPage{}, err
+	}
+	items := make([]MediaItem, 0, len(raw.Items))
+	for _, entry := range raw.Items {
+		items = append(items, entryMedia(entry)...)
+	}
+	return feedPage{
+		items:         items,
+		moreAvailable: raw.MoreAvailable,
+		nextMaxID:     raw.NextMaxID,
+	}, nil
+}
+
+func decodeCollections(body []byte) ([]Collection, bool, string, error) {
+	var raw collectionsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, false, "", err
+	}
+	out := make([]Collection, 0, len(raw.Items))
+	for _, entry := range raw.Items {
+		id := strings.TrimSpace(string(entry.ID))
+		if id == "" || entry.Type == "ALL_MEDIA_AUTO_COLLECTION" {
+			continue
+		}
+		out = append(out, Collection{
+			ID:         id,
+			Name:       strings.TrimSpace(entry.Name),
+			MediaCount: entry.MediaCount,
+		})
+	}
+	return out, raw.MoreAvailable, raw.NextMaxID, nil
+}
@@ -0,0 +1,48 @@
+package instagram
+
+import "testing"
+
+func TestDecodeSavedPage(t *testing.T) {
+	body := []byte(`{
+		"items": [
+			{"media": {"media_type": 1, "code": "S1", "user": {"username": "author"},
+			 "caption": {"text": "saved"}, "image_versions2": {"candidates": [{"url": "img"}]}}}
+		],
+		"more_available": true,
+		"next_max_id": "next"
+	}`)
+	page, err := decodeSavedPage(body)
+	if err != nil {
+		t.Fatalf("decodeSavedPage: %v", err)
+	}
+	if !page.moreAvailable || page.nextMaxID != "next" || len(page.items) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	item := page.items[0]
+	if item.Shortcode != "S1" || item.Username != "author" || item.Caption != "saved" || item.URL != "img" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestDecodeCollections(t *testing.T) {
+	body := []byte(`{
+		"items": [
+			{"collection_id": "1", "collection_name": "All posts", "collection_type": "ALL_MEDIA_AUTO_COLLECTION"},
+			{"collection_id": 17900, "collection_name": " Recipes ", "collection_media_count": 4, "collection_type": "MEDIA"}
+		],
+		"more_available": false
+	}`)
+	collections, more, _, err := decodeCollections(body)
+	if err != nil {
+		t.Fatalf("decodeCollections: %v", err)
+	}
+	if more || len(collections) != 1 {
+		t.Fatalf("expected one collection, got %+v", collections)
+	}
+	if collections[0].ID != "17900" || collections[0].Name != "Recipes" || collections[0].MediaCount != 4 {
+		t.Fatalf("unexpected collection: %+v", collections[0])
+	}
+	if _, ok := FindCollection(collections, "recipes"); !ok {
+		t.Fatalf("expected case-insensitive match")
+	}
+}
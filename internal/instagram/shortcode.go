@@ -0,0 +1,58 @@
+package instagram
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+const shortcodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// shortcodeIDLength is how many leading shortcode characters encode the media
+// id; private posts append more.
+const shortcodeIDLength = 11
+
+// ParseShortcode extracts the shortcode from a post, reel, IGTV or Threads
+// post URL, or returns input unchanged when it is already a bare shortcode.
+func ParseShortcode(input string) string {
+	input = strings.TrimSpace(input)
+	if !strings.Contains(input, "instagram.com") && !isThreadsURL(input) {
+		return strings.Trim(input, "/")
+	}
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "p", "reel", "reels", "tv", "post":
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// ShortcodeToMediaID decodes a shortcode into the numeric media id the
+// private API expects.
+func ShortcodeToMediaID(shortcode string) (string, error) {
+	shortcode = strings.TrimSpace(shortcode)
+	if shortcode == "" {
+		return "", fmt.Errorf("shortcode is required")
+	}
+	if len(shortcode) > shortcodeIDLength {
+		shortcode = shortcode[:shortcodeIDLength]
+	}
+	id := new(big.Int)
+	base := big.NewInt(int64(len(shortcodeAlphabet)))
+	for _, r := range shortcode {
+		digit := strings.IndexRune(shortcodeAlphabet, r)
+		if digit < 0 {
+			return "", fmt.Errorf("invalid shortcode %q", shortcode)
+		}
+		id.Mul(id, base)
+		id.Add(id, big.NewInt(int64(digit)))
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,40 @@
+package instagram
+
+import "testing"
+
+func TestParseShortcode(t *testing.T) {
+	cases := map[string]string{
+		"CxYz123AbC_": "CxYz123AbC_",
+		"https://www.instagram.com/p/CxYz123AbC_/":    "CxYz123AbC_",
+		"https://www.instagram.com/reel/Cabc/?igsh=x": "Cabc",
+		"https://instagram.com/tv/Ctv1/":              "Ctv1",
+		"https://www.instagram.com/someone/p/Cdef/":   "Cdef",
+		"https://www.instagram.com/someone/":          "",
+		"https://www.threads.net/@someone/post/Cthr/": "Cthr",
+	}
+	for input, want := range cases {
+		if got := ParseShortcode(input); got != want {
+			t.Fatalf("ParseShortcode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestShortcodeToMediaID(t *testing.T) {
+	got, err := ShortcodeToMediaID("B")
+	if err != nil || got != "1" {
+		t.Fatalf("expected 1, got %q (%v)", got, err)
+	}
+	got, err = ShortcodeToMediaID("BA")
+	if err != nil || got != "64" {
+		t.Fatalf("expected 64, got %q (%v)", got, err)
+	}
+	// Long private shortcodes only encode the id in their first 11 chars.
+	short, _ := ShortcodeToMediaID("CxYz123AbC_")
+	long, _ := ShortcodeToMediaID("CxYz123AbC_extra")
+	if short != long {
+		t.Fatalf("expected private suffix to be ignored: %q vs %q", short, long)
+	}
+	if _, err := ShortcodeToMediaID("bad!"); err == nil {
+		t.Fatalf("expected error for invalid shortcode")
+	}
+}
@@ -0,0 +1,169 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	reelsMediaURL   = "https://www.instagram.com/api/v1/feed/reels_media/"
+	reelsTrayURL    = "https://www.instagram.com/api/v1/feed/reels_tray/"
+	reelsMediaBatch = 20
+)
+
+type reelsMediaResponse struct {
+	Reels      map[string]storyReel `json:"reels"`
+	ReelsMedia []storyReel          `json:"reels_media"`
+}
+
+type reelsTrayResponse struct {
+	Tray []storyReel `json:"tray"`
+}
+
+type storyReel struct {
+	ID    flexID     `json:"id"`
+	User  feedUser   `json:"user"`
+	Items []feedItem `json:"items"`
+}
+
+// flexID accepts ids encoded either as JSON numbers or strings.
+type flexID string
+
+func (id *flexID) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*id = flexID(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*id = flexID(number.String())
+	return nil
+}
+
+// FetchUserStories returns the active stories of one account.
+func FetchUserStories(ctx context.Context, username, userID string, cookies CookieBundle) ([]MediaItem, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	return fetchReelsMedia(ctx, []string{userID}, username, cookies)
+}
+
+// FetchStoryTray returns the active stories of every account in the story
+// tray of the logged-in user. max caps the number of items (0 = all).
+func FetchStoryTray(ctx context.Context, cookies CookieBundle, max int) ([]MediaItem, error) {
+	body, status, err := doJSONRequestWithLimit(ctx, reelsTrayURL, "", cookies, 8<<20)
+	if err != nil {
+		return nil, fmt.Errorf("story tray request failed (%d): %s", status, errText(err))
+	}
+	var raw reelsTrayResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(raw.Tray))
+	for _, reel := range raw.Tray {
+		if id := strings.TrimSpace(string(reel.ID)); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	out := make([]MediaItem, 0, len(ids))
+	for start := 0; start < len(ids); start += reelsMediaBatch {
+		end := start + reelsMediaBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+		media, err := fetchReelsMedia(ctx, ids[start:end], "", cookies)
+		out = append(out, media...)
+		if err != nil {
+			return out, err
+		}
+		if max > 0 && len(out) >= max {
+			return out[:max], nil
+		}
+	}
+	return out, nil
+}
+
+func fetchReelsMedia(ctx context.Context, reelIDs []string, username string, cookies CookieBundle) ([]MediaItem, error) {
+	reels, err := fetchReels(ctx, reelIDs, username, cookies)
+	if err != nil {
+		return nil, err
+	}
+	return flattenReels(reels), nil
+}
+
+func fetchReels(ctx context.Context, reelIDs []string, username string, cookies CookieBundle) ([]storyReel, error) {
+	query := url.Values{}
+	for _, id := range reelIDs {
+		query.Add("reel_ids", id)
+	}
+	body, status, err := doJSONRequestWithLimit(ctx, reelsMediaURL+"?"+query.Encode(), username, cookies, 8<<20)
+	if err != nil {
+		return nil, fmt.Errorf("stories request failed (%d): %s", status, errText(err))
+	}
+	return decodeReels(body, reelIDs)
+}
+
+// decodeReels reads a reels_media payload in the order of reelIDs. Newer
+// responses list the reels under "reels_media", older ones only key them by
+// id under "reels"; listed reels whose id was not requested as such follow
+// in response order.
+func decodeReels(body []byte, reelIDs []string) ([]storyReel, error) {
+	var raw reelsMediaResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	byID := raw.Reels
+	if len(raw.ReelsMedia) > 0 {
+		byID = make(map[string]storyReel, len(raw.ReelsMedia))
+		for _, reel := range raw.ReelsMedia {
+			byID[string(reel.ID)] = reel
+		}
+	}
+	reels := make([]storyReel, 0, len(byID))
+	for _, id := range reelIDs {
+		if reel, ok := byID[id]; ok {
+			reels = append(reels, reel)
+			delete(byID, id)
+		}
+	}
+	for _, reel := range raw.ReelsMedia {
+		if _, ok := byID[string(reel.ID)]; ok {
+			reels = append(reels, reel)
+			delete(byID, string(reel.ID))
+		}
+	}
+	return reels, nil
+}
+
+// reelMedia maps one reel's items, crediting the reel owner where an item
+// carries no user of its own.
+func reelMedia(reel storyReel) []MediaItem {
+	owner := strings.TrimSpace(reel.User.Username)
+	out := make([]MediaItem, 0, len(reel.Items))
+	for _, item := range reel.Items {
+		for _, media := range feedItemToMedia(item) {
+			if media.Username == "" {
+				media.Username = owner
+			}
+			out = append(out, media)
+		}
+	}
+	return out
+}
+
+func flattenReels(reels []storyReel) []MediaItem {
+	out := make([]MediaItem, 0)
+	for _, reel := range reels {
+		out = append(out, reelMedia(reel)...)
+	}
+	return out
+}
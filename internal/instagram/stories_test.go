@@ -0,0 +1,68 @@
+package instagram
+
+import "testing"
+
+func TestDecodeReelsMedia(t *testing.T) {
+	body := []byte(`{
+		"reels_media": [{
+			"id": 42,
+			"user": {"username": "owner"},
+			"items": [
+				{"media_type": 1, "taken_at": 10, "expiring_at": 86410, "code": "s1",
+				 "image_versions2": {"candidates": [{"url": "img", "width": 10, "height": 10}]}},
+				{"media_type": 2, "taken_at": 11, "expiring_at": 86411, "user": {"username": "poster"},
+				 "image_versions2": {"candidates": [{"url": "thumb", "width": 10, "height": 10}]},
+				 "video_versions": [{"url": "clip.mp4", "width": 720, "height": 1280}]}
+			]
+		}]
+	}`)
+	reels, err := decodeReels(body, []string{"42"})
+	if err != nil {
+		t.Fatalf("decodeReels: %v", err)
+	}
+	items := flattenReels(reels)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].URL != "img" || items[0].Username != "owner" || items[0].ExpiringAt != 86410 {
+		t.Fatalf("unexpected photo story: %+v", items[0])
+	}
+	if !items[1].IsVideo || items[1].VideoURL != "clip.mp4" || items[1].Username != "poster" {
+		t.Fatalf("unexpected video story: %+v", items[1])
+	}
+}
+
+func TestDecodeReelsMediaMapKeepsRequestOrder(t *testing.T) {
+	body := []byte(`{
+		"reels": {
+			"2": {"id": "2", "user": {"username": "b"}, "items": [{"media_type": 1, "image_versions2": {"candidates": [{"url": "b1"}]}}]},
+			"1": {"id": "1", "user": {"username": "a"}, "items": [{"media_type": 1, "image_versions2": {"candidates": [{"url": "a1"}]}}]}
+		}
+	}`)
+	reels, err := decodeReels(body, []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("decodeReels: %v", err)
+	}
+	items := flattenReels(reels)
+	if len(items) != 2 || items[0].URL != "a1" || items[1].URL != "b1" {
+		t.Fatalf("expected a1, b1 in request order, got %+v", items)
+	}
+}
+
+func TestDecodeReelsMediaListKeepsRequestOrder(t *testing.T) {
+	body := []byte(`{
+		"reels_media": [
+			{"id": "x", "items": [{"media_type": 1, "image_versions2": {"candidates": [{"url": "x1"}]}}]},
+			{"id": 2, "items": [{"media_type": 1, "image_versions2": {"candidates": [{"url": "b1"}]}}]},
+			{"id": 1, "items": [{"media_type": 1, "image_versions2": {"candidates": [{"url": "a1"}]}}]}
+		]
+	}`)
+	reels, err := decodeReels(body, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("decodeReels: %v", err)
+	}
+	items := flattenReels(reels)
+	if len(items) != 3 || items[0].URL != "a1" || items[1].URL != "b1" || items[2].URL != "x1" {
+		t.Fatalf("expected a1, b1, then the unrequested x1, got %+v", items)
+	}
+}
@@ -0,0 +1,229 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SyncState is the per-account bookmark persisted between incremental syncs.
+//
+// NewestTakenAt/NewestShortcode mark the head of the last completed sync.
+// Cursor is only set while a crawl is unfinished; it holds the next_max_id to
+// resume from, and CursorTakenAt/CursorShortcode the newest post that crawl
+// had seen when it started.
+type SyncState struct {
+	Username        string `json:"username"`
+	UserID          string `json:"user_id,omitempty"`
+	NewestShortcode string `json:"newest_shortcode,omitempty"`
+	NewestTakenAt   int64  `json:"newest_taken_at,omitempty"`
+	Cursor          string `json:"cursor,omitempty"`
+	CursorShortcode string `json:"cursor_shortcode,omitempty"`
+	CursorTakenAt   int64  `json:"cursor_taken_at,omitempty"`
+	UpdatedAt       int64  `json:"updated_at,omitempty"`
+}
+
+// LoadSyncState reads the state file at path. A missing file yields an empty
+// state so the first sync crawls the whole account.
+func LoadSyncState(path string) (SyncState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SyncState{}, nil
+	}
+	if err != nil {
+		return SyncState{}, err
+	}
+	var state SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return SyncState{}, err
+	}
+	return state, nil
+}
+
+// SaveSyncState atomically replaces the state file at path.
+func SaveSyncState(path string, state SyncState) error {
+	state.UpdatedAt = time.Now().Unix()
+	encoded, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".metcli-state-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(encoded, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SyncUserMedia pages the user feed from the top and stops as soon as it
+// reaches posts recorded in state, then finishes any crawl that a previous run
+// left behind. onPage receives each page of new items together with the state
+// that should be persisted once those items are stored.
+func SyncUserMedia(
+	ctx context.Context,
+	username string,
+	userID string,
+	cookies CookieBundle,
+	state SyncState,
+	pageSize int,
+	onPage func(items []MediaItem, state SyncState) error,
+) (SyncState, error) {
+	state.Username = username
+	state.UserID = userID
+	fetch := func(ctx context.Context, maxID string) (feedPage, error) {
+		page, err := fetchUserFeedPage(ctx, username, userID, maxID, pageSize, cookies)
+		for i := range page.items {
+			if strings.TrimSpace(page.items[i].Username) == "" {
+				page.items[i].Username = username
+			}
+		}
+		return page, err
+	}
+	return syncFeed(ctx, fetch, state, onPage)
+}
+
+type syncMark struct {
+	takenAt   int64
+	shortcode string
+}
+
+func (m syncMark) known(item MediaItem) bool {
+	if m.shortcode != "" && item.Shortcode == m.shortcode {
+		return true
+	}
+	return m.takenAt > 0 && item.TakenAt > 0 && item.TakenAt <= m.takenAt
+}
+
+func (m *syncMark) observe(item MediaItem) {
+	if item.TakenAt > m.takenAt {
+		m.takenAt = item.TakenAt
+		m.shortcode = item.Shortcode
+	}
+}
+
+func syncFeed(
+	ctx context.Context,
+	fetch pageFetcher,
+	state SyncState,
+	onPage func(items []MediaItem, state SyncState) error,
+) (SyncState, error) {
+	resuming := state.Cursor != ""
+	headStop := syncMark{takenAt: state.NewestTakenAt, shortcode: state.NewestShortcode}
+	if resuming {
+		headStop = syncMark{takenAt: state.CursorTakenAt, shortcode: state.CursorShortcode}
+	}
+	newest := headStop
+
+	// Head pass: everything posted since the last sync (or since the
+	// interrupted crawl started). A fresh crawl records its cursor as it goes
+	// so it can be resumed; a resumed one keeps the stored cursor untouched.
+	complete, err := crawlUntil(ctx, fetch, "", headStop, func(items []MediaItem, nextMaxID string, done bool) error {
+		for _, item := range items {
+			newest.observe(item)
+		}
+		if !resuming {
+			state.Cursor = ""
+			if !done {
+				state.Cursor = nextMaxID
+			}
+			state.CursorTakenAt = newest.takenAt
+			state.CursorShortcode = newest.shortcode
+		}
+		return onPage(items, state)
+	})
+	if err != nil {
+		return state, err
+	}
+	if !complete {
+		return state, nil
+	}
+
+	if resuming {
+		state.CursorTakenAt = newest.takenAt
+		state.CursorShortcode = newest.shortcode
+		tailStop := syncMark{takenAt: state.NewestTakenAt, shortcode: state.NewestShortcode}
+		complete, err = crawlUntil(ctx, fetch, state.Cursor, tailStop, func(items []MediaItem, nextMaxID string, done bool) error {
+			state.Cursor = ""
+			if !done {
+				state.Cursor = nextMaxID
+			}
+			return onPage(items, state)
+		})
+		if err != nil {
+			return state, err
+		}
+		if !complete {
+			return state, nil
+		}
+	}
+
+	state.NewestTakenAt = newest.takenAt
+	state.NewestShortcode = newest.shortcode
+	state.Cursor = ""
+	state.CursorTakenAt = 0
+	state.CursorShortcode = ""
+	return state, nil
+}
+
+// crawlUntil pages from maxID until it meets an item known to stop, or the
+// feed ends. Pinned posts sit at the top regardless of age, so they never end
+// the crawl; known pinned posts are just skipped. It reports false when the
+// page budget ran out before the crawl finished.
+func crawlUntil(
+	ctx context.Context,
+	fetch pageFetcher,
+	maxID string,
+	stop syncMark,
+	onPage func(items []MediaItem, nextMaxID string, done bool) error,
+) (bool, error) {
+	for pageCount := 1; ; pageCount++ {
+		page, err := fetch(ctx, maxID)
+		if err != nil {
+			return false, err
+		}
+
+		fresh := make([]MediaItem, 0, len(page.items))
+		reachedKnown := false
+		for _, item := range page.items {
+			if item.URL == "" {
+				continue
+			}
+			if !stop.known(item) {
+				fresh = append(fresh, item)
+				continue
+			}
+			if item.Pinned {
+				continue
+			}
+			reachedKnown = true
+			break
+		}
+
+		done := reachedKnown ||
+			!page.moreAvailable ||
+			page.nextMaxID == "" ||
+			page.nextMaxID == maxID
+		if err := onPage(fresh, page.nextMaxID, done); err != nil {
+			return false, err
+		}
+		if done {
+			return true, nil
+		}
+		maxID = page.nextMaxID
+		if pageCount > 200 {
+			return false, nil
+		}
+	}
+}
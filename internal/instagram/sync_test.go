@@ -0,0 +1,128 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+type fakeFeed struct {
+	pages map[string]feedPage
+	fail  map[string]bool
+	calls []string
+}
+
+func (f *fakeFeed) fetch(_ context.Context, maxID string) (feedPage, error) {
+	f.calls = append(f.calls, maxID)
+	if f.fail[maxID] {
+		return feedPage{}, errors.New("boom")
+	}
+	return f.pages[maxID], nil
+}
+
+func post(code string, takenAt int64) MediaItem {
+	return MediaItem{URL: "u-" + code, Shortcode: code, TakenAt: takenAt}
+}
+
+func collectSync(t *testing.T, feed *fakeFeed, state SyncState) ([]string, SyncState, SyncState, error) {
+	t.Helper()
+	var got []string
+	var persisted SyncState
+	final, err := syncFeed(context.Background(), feed.fetch, state, func(items []MediaItem, s SyncState) error {
+		for _, item := range items {
+			got = append(got, item.Shortcode)
+		}
+		persisted = s
+		return nil
+	})
+	return got, persisted, final, err
+}
+
+func TestSyncFeedFirstRunCrawlsEverything(t *testing.T) {
+	feed := &fakeFeed{pages: map[string]feedPage{
+		"":   {items: []MediaItem{post("c", 30), post("b", 20)}, moreAvailable: true, nextMaxID: "p2"},
+		"p2": {items: []MediaItem{post("a", 10)}},
+	}}
+	got, _, final, err := collectSync(t, feed, SyncState{})
+	if err != nil {
+		t.Fatalf("syncFeed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 items, got %v", got)
+	}
+	if final.NewestShortcode != "c" || final.NewestTakenAt != 30 || final.Cursor != "" {
+		t.Fatalf("unexpected final state: %+v", final)
+	}
+}
+
+func TestSyncFeedStopsAtKnownItems(t *testing.T) {
+	feed := &fakeFeed{pages: map[string]feedPage{
+		"":   {items: []MediaItem{post("old-pinned", 5), post("d", 40), post("c", 30), post("b", 20)}, moreAvailable: true, nextMaxID: "p2"},
+		"p2": {items: []MediaItem{post("a", 10)}},
+	}}
+	feed.pages[""].items[0].Pinned = true
+	got, _, final, err := collectSync(t, feed, SyncState{NewestShortcode: "c", NewestTakenAt: 30})
+	if err != nil {
+		t.Fatalf("syncFeed: %v", err)
+	}
+	if len(got) != 1 || got[0] != "d" {
+		t.Fatalf("expected only d, got %v", got)
+	}
+	if len(feed.calls) != 1 {
+		t.Fatalf("expected a single page request, got %v", feed.calls)
+	}
+	if final.NewestShortcode != "d" || final.NewestTakenAt != 40 {
+		t.Fatalf("unexpected final state: %+v", final)
+	}
+}
+
+func TestSyncFeedResumesInterruptedCrawl(t *testing.T) {
+	feed := &fakeFeed{
+		pages: map[string]feedPage{
+			"":   {items: []MediaItem{post("c", 30), post("b", 20)}, moreAvailable: true, nextMaxID: "p2"},
+			"p2": {items: []MediaItem{post("a", 10)}},
+		},
+		fail: map[string]bool{"p2": true},
+	}
+	_, persisted, _, err := collectSync(t, feed, SyncState{})
+	if err == nil {
+		t.Fatalf("expected error from interrupted crawl")
+	}
+	if persisted.Cursor != "p2" || persisted.CursorShortcode != "c" || persisted.NewestTakenAt != 0 {
+		t.Fatalf("expected resumable state, got %+v", persisted)
+	}
+
+	feed.fail = nil
+	feed.calls = nil
+	feed.pages[""] = feedPage{items: []MediaItem{post("d", 40), post("c", 30), post("b", 20)}, moreAvailable: true, nextMaxID: "p2"}
+	got, _, final, err := collectSync(t, feed, persisted)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(got) != 2 || got[0] != "d" || got[1] != "a" {
+		t.Fatalf("expected d then a, got %v", got)
+	}
+	if final.Cursor != "" || final.NewestShortcode != "d" {
+		t.Fatalf("unexpected final state: %+v", final)
+	}
+}
+
+func TestSyncStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadSyncState(path)
+	if err != nil || state.Cursor != "" {
+		t.Fatalf("expected empty state for missing file, got %+v (%v)", state, err)
+	}
+	want := SyncState{Username: "u", NewestShortcode: "abc", NewestTakenAt: 12, Cursor: "next"}
+	if err := SaveSyncState(path, want); err != nil {
+		t.Fatalf("SaveSyncState: %v", err)
+	}
+	got, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatalf("LoadSyncState: %v", err)
+	}
+	if got.NewestShortcode != "abc" || got.Cursor != "next" || got.UpdatedAt == 0 {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+}
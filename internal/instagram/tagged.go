@@ -0,0 +1,67 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FetchTaggedMedia pages the tagged tab ("photos of you") of an account.
+// Username stays the author of each post; TaggedAccount is set to username.
+func FetchTaggedMedia(
+	ctx context.Context,
+	username string,
+	userID string,
+	cookies CookieBundle,
+	limit Limit,
+	pageSize int,
+) ([]MediaItem, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 50 {
+		pageSize = 50
+	}
+
+	return collectTagged(ctx, username, func(ctx context.Context, maxID string) (feedPage, error) {
+		return fetchTaggedPage(ctx, username, userID, maxID, pageSize, cookies)
+	}, limit)
+}
+
+// collectTagged pages the tagged tab of username and marks every item with
+// it as TaggedAccount.
+func collectTagged(ctx context.Context, username string, fetch pageFetcher, limit Limit) ([]MediaItem, error) {
+	out, err := collectFeed(ctx, fetch, limit)
+	for i := range out {
+		out[i].TaggedAccount = username
+	}
+	return out, err
+}
+
+func fetchTaggedPage(
+	ctx context.Context,
+	username string,
+	userID string,
+	maxID string,
+	pageSize int,
+	cookies CookieBundle,
+) (feedPage, error) {
+	endpoint := fmt.Sprintf(
+		"https://www.instagram.com/api/v1/usertags/%s/feed/?count=%d",
+		url.PathEscape(userID),
+		pageSize,
+	)
+	if strings.TrimSpace(maxID) != "" {
+		endpoint += "&max_id=" + url.QueryEscape(maxID)
+	}
+	body, status, err := doJSONRequestWithLimit(ctx, endpoint, username, cookies, 4<<20)
+	if err != nil {
+		return feedPage{}, fmt.Errorf("tagged request failed (%d): %s", status, errText(err))
+	}
+	return decodeFeedPage(body)
+}
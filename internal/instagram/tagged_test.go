@@ -0,0 +1,56 @@
+package instagram
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollectTagged(t *testing.T) {
+	first, err := decodeFeedPage([]byte(`{
+		"items": [
+			{"media_type": 1, "code": "T1", "user": {"username": "author"},
+			 "image_versions2": {"candidates": [{"url": "img1"}]}},
+			{"media_type": 8, "code": "T2", "user": {"username": "friend"},
+			 "carousel_media": [
+				{"media_type": 1, "image_versions2": {"candidates": [{"url": "c1"}]}},
+				{"media_type": 1, "image_versions2": {"candidates": [{"url": "c2"}]}}
+			 ]}
+		],
+		"more_available": true,
+		"next_max_id": "p2"
+	}`))
+	if err != nil {
+		t.Fatalf("decodeFeedPage: %v", err)
+	}
+	second, err := decodeFeedPage([]byte(`{
+		"items": [
+			{"media_type": 1, "code": "T3", "user": {"username": "author"},
+			 "image_versions2": {"candidates": [{"url": "img3"}]}}
+		],
+		"more_available": false
+	}`))
+	if err != nil {
+		t.Fatalf("decodeFeedPage: %v", err)
+	}
+	feed := &fakeFeed{pages: map[string]feedPage{"": first, "p2": second}}
+
+	items, err := collectTagged(context.Background(), "me", feed.fetch, Limit{})
+	if err != nil {
+		t.Fatalf("collectTagged: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("expected 4 items, got %d", len(items))
+	}
+	authors := []string{"author", "friend", "friend", "author"}
+	for i, item := range items {
+		if item.Username != authors[i] {
+			t.Fatalf("item %d: expected author %q, got %q", i, authors[i], item.Username)
+		}
+		if item.TaggedAccount != "me" {
+			t.Fatalf("item %d: expected tagged account me, got %q", i, item.TaggedAccount)
+		}
+	}
+	if len(feed.calls) != 2 {
+		t.Fatalf("expected both pages fetched, got %v", feed.calls)
+	}
+}
@@ -0,0 +1,249 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// threadsAppID identifies the Threads web app; Threads runs on the Instagram
+// session, so the same cookies work for both.
+const threadsAppID = "238260118697367"
+
+// ThreadsPost is a post on Threads. Replies carry ReplyTo, the author of the
+// post they answer.
+type ThreadsPost struct {
+	ID         string
+	Code       string
+	Username   string
+	Text       string
+	TakenAt    int64
+	LikeCount  int64
+	ReplyCount int64
+	ReplyTo    string
+	LinkURL    string
+	Media      []MediaItem
+}
+
+// URL links to the post on threads.net.
+func (p ThreadsPost) URL() string {
+	if p.Code == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://www.threads.net/@%s/post/%s", p.Username, p.Code)
+}
+
+// ParseThreadsUsername accepts "name", "@name" or a threads.net profile URL.
+func ParseThreadsUsername(input string) string {
+	input = strings.TrimSpace(input)
+	if isThreadsURL(input) {
+		parsed, err := url.Parse(input)
+		if err != nil {
+			return ""
+		}
+		input = strings.Split(strings.Trim(parsed.Path, "/"), "/")[0]
+	}
+	return strings.TrimPrefix(strings.Trim(input, "/"), "@")
+}
+
+func isThreadsURL(input string) bool {
+	return strings.Contains(input, "threads.net") || strings.Contains(input, "threads.com")
+}
+
+type threadsResponse struct {
+	Threads   []threadsThread `json:"threads"`
+	NextMaxID string          `json:"next_max_id"`
+}
+
+type threadsThread struct {
+	ThreadItems []struct {
+		Post threadsRawPost `json:"post"`
+	} `json:"thread_items"`
+}
+
+type threadsRawPost struct {
+	feedItem
+	TextPostAppInfo struct {
+		DirectReplyCount      int64     `json:"direct_reply_count"`
+		ReplyToAuthor         *feedUser `json:"reply_to_author"`
+		LinkPreviewAttachment *struct {
+			URL string `json:"url"`
+		} `json:"link_preview_attachment"`
+	} `json:"text_post_app_info"`
+}
+
+type threadsRepliesResponse struct {
+	ContainingThread threadsThread   `json:"containing_thread"`
+	ReplyThreads     []threadsThread `json:"reply_threads"`
+	PagingTokens     struct {
+		Downwards string `json:"downwards"`
+	} `json:"paging_tokens"`
+	DownwardsThreadWillContinue bool `json:"downwards_thread_will_continue"`
+}
+
+// FetchThreads pages the threads an account posted, or with replies set the
+// replies tab. max caps the number of posts (0 = all).
+func FetchThreads(
+	ctx context.Context,
+	username string,
+	userID string,
+	cookies CookieBundle,
+	replies bool,
+	max int,
+) ([]ThreadsPost, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("user id is required")
+	}
+	endpoint := fmt.Sprintf("https://i.instagram.com/api/v1/text_feed/%s/profile/", url.PathEscape(userID))
+	if replies {
+		endpoint += "replies/"
+	}
+
+	out := make([]ThreadsPost, 0)
+	seen := map[string]struct{}{}
+	maxID := ""
+	for page := 0; page < 200; page++ {
+		pageURL := endpoint
+		if maxID != "" {
+			pageURL += "?max_id=" + url.QueryEscape(maxID)
+		}
+		body, status, err := doThreadsRequest(ctx, pageURL, username, cookies)
+		if err != nil {
+			return out, fmt.Errorf("threads request failed (%d): %s", status, errText(err))
+		}
+		posts, next, err := decodeThreadsPage(body)
+		if err != nil {
+			return out, err
+		}
+		for _, post := range posts {
+			if _, ok := seen[post.ID]; ok {
+				continue
+			}
+			seen[post.ID] = struct{}{}
+			out = append(out, post)
+		}
+		if max > 0 && len(out) >= max {
+			return out[:max], nil
+		}
+		if next == "" || next == maxID {
+			break
+		}
+		maxID = next
+	}
+	return out, nil
+}
+
+// FetchThreadsPost loads a post by shortcode together with its replies, up
+// to max replies (0 = all).
+func FetchThreadsPost(ctx context.Context, shortcode string, cookies CookieBundle, max int) (ThreadsPost, []ThreadsPost, error) {
+	mediaID, err := ShortcodeToMediaID(shortcode)
+	if err != nil {
+		return ThreadsPost{}, nil, err
+	}
+	endpoint := fmt.Sprintf("https://i.instagram.com/api/v1/text_feed/%s/replies/", url.PathEscape(mediaID))
+
+	var post ThreadsPost
+	found := false
+	replies := make([]ThreadsPost, 0)
+	token := ""
+	for page := 0; page < 200; page++ {
+		pageURL := endpoint
+		if token != "" {
+			pageURL += "?paging_token=" + url.QueryEscape(token)
+		}
+		body, status, err := doThreadsRequest(ctx, pageURL, "", cookies)
+		if err != nil {
+			if found {
+				return post, replies, fmt.Errorf("threads replies request failed (%d): %s", status, errText(err))
+			}
+			return ThreadsPost{}, nil, fmt.Errorf("threads post request failed (%d): %s", status, errText(err))
+		}
+		var raw threadsRepliesResponse
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return post, replies, err
+		}
+		if !found {
+			// The containing thread lists the parents of a reply first.
+			items := threadPosts(raw.ContainingThread)
+			if len(items) == 0 {
+				return ThreadsPost{}, nil, fmt.Errorf("threads post %s not found", strings.TrimSpace(shortcode))
+			}
+			post = items[len(items)-1]
+			found = true
+		}
+		for _, thread := range raw.ReplyThreads {
+			replies = append(replies, threadPosts(thread)...)
+		}
+		if max > 0 && len(replies) >= max {
+			return post, replies[:max], nil
+		}
+		next := raw.PagingTokens.Downwards
+		if !raw.DownwardsThreadWillContinue || next == "" || next == token {
+			break
+		}
+		token = next
+	}
+	return post, replies, nil
+}
+
+func decodeThreadsPage(body []byte) ([]ThreadsPost, string, error) {
+	var raw threadsResponse
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, "", err
+	}
+	posts := make([]ThreadsPost, 0, len(raw.Threads))
+	for _, thread := range raw.Threads {
+		posts = append(posts, threadPosts(thread)...)
+	}
+	return posts, raw.NextMaxID, nil
+}
+
+func threadPosts(thread threadsThread) []ThreadsPost {
+	posts := make([]ThreadsPost, 0, len(thread.ThreadItems))
+	for _, entry := range thread.ThreadItems {
+		raw := entry.Post
+		id := strings.TrimSpace(string(raw.PK))
+		if id == "" {
+			continue
+		}
+		post := ThreadsPost{
+			ID:         id,
+			Code:       raw.Code,
+			Username:   strings.TrimSpace(raw.User.Username),
+			Text:       itemCaption(raw.feedItem),
+			TakenAt:    raw.TakenAt,
+			LikeCount:  raw.LikeCount,
+			ReplyCount: raw.TextPostAppInfo.DirectReplyCount,
+			Media:      feedItemToMedia(raw.feedItem),
+		}
+		if author := raw.TextPostAppInfo.ReplyToAuthor; author != nil {
+			post.ReplyTo = strings.TrimSpace(author.Username)
+		}
+		if link := raw.TextPostAppInfo.LinkPreviewAttachment; link != nil {
+			post.LinkURL = link.URL
+		}
+		posts = append(posts, post)
+	}
+	return posts
+}
+
+// doThreadsRequest GETs a Threads endpoint with the Threads app id in place
+// of Instagram's.
+func doThreadsRequest(ctx context.Context, endpoint string, username string, cookies CookieBundle) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	applyHeaders(req, "", cookies)
+	req.Header.Set("X-IG-App-ID", threadsAppID)
+	req.Header.Set("Origin", "https://www.threads.net")
+	req.Header.Set("Referer", "https://www.threads.net/")
+	if strings.TrimSpace(username) != "" {
+		req.Header.Set("Referer", fmt.Sprintf("https://www.threads.net/@%s", username))
+	}
+	return sendWithLimit(req, 4<<20)
+}
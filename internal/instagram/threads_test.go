@@ -0,0 +1,56 @@
+package instagram
+
+import "testing"
+
+func TestDecodeThreadsPage(t *testing.T) {
+	body := []byte(`{
+		"threads": [
+			{"thread_items": [{"post": {
+				"pk": 3100, "code": "T1", "taken_at": 70, "media_type": 19, "like_count": 4,
+				"user": {"username": "tester"},
+				"caption": {"text": "just text"},
+				"text_post_app_info": {"direct_reply_count": 2, "link_preview_attachment": {"url": "https://example.com"}}
+			}}]},
+			{"thread_items": [{"post": {
+				"pk": "3101", "code": "T2", "media_type": 1,
+				"user": {"username": "tester"},
+				"caption": {"text": "with photo"},
+				"image_versions2": {"candidates": [{"url": "img", "width": 1080, "height": 1350}]},
+				"text_post_app_info": {"reply_to_author": {"username": "friend"}}
+			}}]}
+		],
+		"next_max_id": "next"
+	}`)
+	posts, next, err := decodeThreadsPage(body)
+	if err != nil {
+		t.Fatalf("decodeThreadsPage: %v", err)
+	}
+	if next != "next" || len(posts) != 2 {
+		t.Fatalf("unexpected page: %q %+v", next, posts)
+	}
+	first := posts[0]
+	if first.ID != "3100" || first.Text != "just text" || first.ReplyCount != 2 || len(first.Media) != 0 {
+		t.Fatalf("unexpected text post: %+v", first)
+	}
+	if first.LinkURL != "https://example.com" || first.URL() != "https://www.threads.net/@tester/post/T1" {
+		t.Fatalf("unexpected links: %q %q", first.LinkURL, first.URL())
+	}
+	second := posts[1]
+	if second.ReplyTo != "friend" || len(second.Media) != 1 || second.Media[0].URL != "img" {
+		t.Fatalf("unexpected reply: %+v", second)
+	}
+}
+
+func TestParseThreadsUsername(t *testing.T) {
+	cases := map[string]string{
+		"zuck":                                  "zuck",
+		"@zuck":                                 "zuck",
+		"https://www.threads.net/@zuck":         "zuck",
+		"https://www.threads.net/@zuck/post/C1": "zuck",
+	}
+	for input, want := range cases {
+		if got := ParseThreadsUsername(input); got != want {
+			t.Fatalf("ParseThreadsUsername(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -17,14 +17,20 @@ import (
 	_ "golang.org/x/image/webp"
 )
 
+const (
+	KindAvatar = "avatar"
+	KindMedia  = "media"
+	KindStory  = "story"
+
+	KindHighlight      = "highlight"
+	KindHighlightCover = "highlight_cover"
+)
+
+// Item is a renderable entry: a MediaItem tagged with what it is (avatar,
+// media, ...).
 type Item struct {
-	URL       string
-	Kind      string
-	IsVideo   bool
-	Shortcode string
-	TakenAt   int64
-	Username  string
-	Caption   string
+	Kind string
+	MediaItem
 }
 
 func ParseUsername(input string) string {
@@ -43,7 +49,14 @@ func ParseUsername(input string) string {
 		return input
 	}
 	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
-	if len(segments) == 0 {
+	switch segments[0] {
+	case "p", "reel", "reels", "tv":
+		// Post links carry no username.
+		return ""
+	case "stories":
+		if len(segments) > 1 {
+			return segments[1]
+		}
 		return ""
 	}
 	return segments[0]
@@ -58,26 +71,28 @@ func BuildItems(profile Profile, includeAvatar bool, includeVideos bool) []Item
 		}
 		if avatarURL != "" {
 			items = append(items, Item{
-				URL:  avatarURL,
-				Kind: "avatar",
+				Kind:      KindAvatar,
+				MediaItem: MediaItem{URL: avatarURL},
 			})
 		}
 	}
-	for _, media := range profile.Media {
-		if media.URL == "" {
+	return append(items, TagItems(KindMedia, profile.Media, includeVideos)...)
+}
+
+// TagItems wraps media as Items of the given kind, dropping entries without a
+// URL and videos unless includeVideos is set.
+func TagItems(kind string, media []MediaItem, includeVideos bool) []Item {
+	items := make([]Item, 0, len(media))
+	for _, m := range media {
+		if m.URL == "" {
 			continue
 		}
-		if media.IsVideo && !includeVideos {
+		if m.IsVideo && !includeVideos {
 			continue
 		}
 		items = append(items, Item{
-			URL:       media.URL,
-			Kind:      "media",
-			IsVideo:   media.IsVideo,
-			Shortcode: media.Shortcode,
-			TakenAt:   media.TakenAt,
-			Username:  media.Username,
-			Caption:   media.Caption,
+			Kind:      kind,
+			MediaItem: m,
 		})
 	}
 	return items
@@ -98,18 +113,10 @@ func DownloadImage(
 	username string,
 	cookies CookieBundle,
 ) ([]byte, int, int, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imgURL, nil)
+	req, err := newMediaRequest(ctx, imgURL, "image/jpeg,image/png,image/*;q=0.8,*/*;q=0.5", username, cookies)
 	if err != nil {
 		return nil, 0, 0, err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-	req.Header.Set("Accept", "image/jpeg,image/png,image/*;q=0.8,*/*;q=0.5")
-	if cookies.Header != "" {
-		req.Header.Set("Cookie", cookies.Header)
-	}
-	if strings.TrimSpace(username) != "" {
-		req.Header.Set("Referer", fmt.Sprintf("https://www.instagram.com/%s/", username))
-	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -131,6 +138,28 @@ func DownloadImage(
 	return data, cfg.Width, cfg.Height, nil
 }
 
+func newMediaRequest(
+	ctx context.Context,
+	mediaURL string,
+	accept string,
+	username string,
+	cookies CookieBundle,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mediaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	req.Header.Set("Accept", accept)
+	if cookies.Header != "" {
+		req.Header.Set("Cookie", cookies.Header)
+	}
+	if strings.TrimSpace(username) != "" {
+		req.Header.Set("Referer", fmt.Sprintf("https://www.instagram.com/%s/", username))
+	}
+	return req, nil
+}
+
 func EnsurePNG(data []byte) ([]byte, error) {
 	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
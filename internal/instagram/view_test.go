@@ -10,11 +10,14 @@ import (
 
 func TestParseUsername(t *testing.T) {
 	cases := map[string]string{
-		"@sportg33k":                         "sportg33k",
-		" sportg33k ":                        "sportg33k",
-		"https://www.instagram.com/foo/":     "foo",
-		"https://instagram.com/bar/":         "bar",
-		"https://www.instagram.com/baz/reel": "baz",
+		"@sportg33k":                                 "sportg33k",
+		" sportg33k ":                                "sportg33k",
+		"https://www.instagram.com/foo/":             "foo",
+		"https://instagram.com/bar/":                 "bar",
+		"https://www.instagram.com/baz/reel":         "baz",
+		"https://www.instagram.com/p/ABC123/":        "",
+		"https://www.instagram.com/reel/XYZ/":        "",
+		"https://www.instagram.com/stories/qux/123/": "qux",
 	}
 	for input, want := range cases {
 		if got := ParseUsername(input); got != want {
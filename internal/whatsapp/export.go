@@ -0,0 +1,129 @@
+package whatsapp
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Export is a chat export: the transcript plus the media files that were
+// shipped with it, read from the ZIP or from the transcript's directory.
+type Export struct {
+	Name     string
+	Messages []Message
+
+	fsys   fs.FS
+	closer io.Closer
+}
+
+// Open reads the export at path, either the ZIP WhatsApp shares or an
+// extracted .txt transcript.
+func Open(path string, loc *time.Location) (*Export, error) {
+	if strings.EqualFold(filepath.Ext(path), ".txt") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return newExport(os.DirFS(filepath.Dir(path)), nil, filepath.Base(path), data, loc)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open chat export %s: %w", path, err)
+	}
+	name, err := transcriptName(zr)
+	if err != nil {
+		_ = zr.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	data, err := fs.ReadFile(zr, name)
+	if err != nil {
+		_ = zr.Close()
+		return nil, err
+	}
+	export, err := newExport(zr, zr, name, data, loc)
+	if err != nil {
+		_ = zr.Close()
+		return nil, err
+	}
+	return export, nil
+}
+
+func newExport(fsys fs.FS, closer io.Closer, name string, data []byte, loc *time.Location) (*Export, error) {
+	messages, err := Parse(bytes.NewReader(data), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &Export{Name: chatName(name), Messages: messages, fsys: fsys, closer: closer}, nil
+}
+
+// transcriptName finds the chat text in a ZIP. iOS names it _chat.txt,
+// Android after the chat ("WhatsApp Chat with Alice.txt").
+func transcriptName(zr *zip.ReadCloser) (string, error) {
+	var candidates []string
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".txt") {
+			continue
+		}
+		if path.Base(file.Name) == "_chat.txt" {
+			return file.Name, nil
+		}
+		candidates = append(candidates, file.Name)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no chat transcript found")
+	}
+	sort.Strings(candidates)
+	for _, name := range candidates {
+		if strings.HasPrefix(path.Base(name), "WhatsApp Chat") {
+			return name, nil
+		}
+	}
+	return candidates[0], nil
+}
+
+func chatName(file string) string {
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	if name == "_chat" {
+		return ""
+	}
+	for _, prefix := range []string{"WhatsApp Chat with ", "WhatsApp Chat - "} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return name
+}
+
+func (e *Export) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// ReadFile reads an attachment referenced by a message.
+func (e *Export) ReadFile(name string) ([]byte, error) {
+	name = path.Clean(strings.TrimSpace(name))
+	if name == "." || strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return fs.ReadFile(e.fsys, name)
+}
+
+// IsImage reports whether an attachment name looks like a still image.
+func IsImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+// Package whatsapp parses WhatsApp "Export chat" transcripts.
+package whatsapp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is one chat entry. System messages (encryption notices, group
+// changes, ...) have no sender. Attachment names a file that ships next to
+// the transcript; MediaOmitted marks media left out of the export.
+type Message struct {
+	Time         time.Time
+	Sender       string
+	Text         string
+	System       bool
+	Attachment   string
+	MediaOmitted bool
+}
+
+var (
+	// [31/12/2023, 21:41:05] Name: text (iOS)
+	bracketHeader = regexp.MustCompile(`^\[(\d{1,4}[./-]\d{1,2}[./-]\d{1,4}),? (\d{1,2}[:.]\d{2}(?:[:.]\d{2})?)(?: ?([AaPp])\.? ?[Mm]\.?)?\] (.*)$`)
+	// 31/12/2023, 21:41 - Name: text (Android)
+	dashHeader = regexp.MustCompile(`^(\d{1,4}[./-]\d{1,2}[./-]\d{1,4}),? (\d{1,2}[:.]\d{2}(?:[:.]\d{2})?)(?: ?([AaPp])\.? ?[Mm]\.?)? [-–] (.*)$`)
+
+	attachedTag  = regexp.MustCompile(`<attached: ([^>]+)>`)
+	fileAttached = regexp.MustCompile(`^(.+?\.[A-Za-z0-9]{2,5}) \(file attached\)`)
+)
+
+// omittedMarkers are the placeholders both platforms write for media left out
+// of an export.
+var omittedMarkers = []string{
+	"<Media omitted>",
+	"<Medien ausgeschlossen>",
+	"image omitted",
+	"video omitted",
+	"audio omitted",
+	"sticker omitted",
+	"GIF omitted",
+	"document omitted",
+}
+
+type rawMessage struct {
+	date     [3]int
+	dateLen  [3]int
+	clock    string
+	meridiem string
+	body     string
+}
+
+// Parse reads a chat transcript. Exports carry no time zone, so times are
+// placed in loc. Day/month order is inferred from the whole transcript since
+// it depends on the exporting phone's locale.
+func Parse(r io.Reader, loc *time.Location) ([]Message, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var raws []rawMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	first := true
+	for scanner.Scan() {
+		line := normalizeLine(scanner.Text())
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+		if raw, ok := parseHeader(line); ok {
+			raws = append(raws, raw)
+			continue
+		}
+		// Continuation of a multi-line message.
+		if len(raws) == 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("unrecognized chat line: %q", line)
+		}
+		raws[len(raws)-1].body += "\n" + line
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	order := inferDateOrder(raws)
+	senders := senderCounts(raws)
+	out := make([]Message, 0, len(raws))
+	for _, raw := range raws {
+		ts, err := raw.time(order, loc)
+		if err != nil {
+			return out, err
+		}
+		msg := parseBody(raw.body, senders)
+		msg.Time = ts
+		out = append(out, msg)
+	}
+	return out, nil
+}
+
+// normalizeLine maps the narrow and non-breaking spaces newer exports put
+// before AM/PM to plain spaces and drops a leading left-to-right mark.
+func normalizeLine(line string) string {
+	line = strings.NewReplacer("\u202f", " ", "\u00a0", " ").Replace(line)
+	return strings.TrimRight(strings.TrimPrefix(line, "\u200e"), "\r")
+}
+
+func parseHeader(line string) (rawMessage, bool) {
+	match := bracketHeader.FindStringSubmatch(line)
+	if match == nil {
+		match = dashHeader.FindStringSubmatch(line)
+	}
+	if match == nil {
+		return rawMessage{}, false
+	}
+	parts := strings.FieldsFunc(match[1], func(r rune) bool { return r == '.' || r == '/' || r == '-' })
+	if len(parts) != 3 {
+		return rawMessage{}, false
+	}
+	raw := rawMessage{clock: match[2], meridiem: strings.ToUpper(match[3]), body: match[4]}
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return rawMessage{}, false
+		}
+		raw.date[i] = value
+		raw.dateLen[i] = len(part)
+	}
+	return raw, true
+}
+
+type dateOrder int
+
+const (
+	orderDMY dateOrder = iota
+	orderMDY
+	orderYMD
+)
+
+// inferDateOrder picks the field order that makes every date valid,
+// preferring month-first for 12-hour clocks as US locales use both.
+func inferDateOrder(raws []rawMessage) dateOrder {
+	firstOver12, secondOver12, twelveHour := false, false, false
+	for _, raw := range raws {
+		if raw.dateLen[0] == 4 {
+			return orderYMD
+		}
+		if raw.date[0] > 12 {
+			firstOver12 = true
+		}
+		if raw.date[1] > 12 {
+			secondOver12 = true
+		}
+		if raw.meridiem != "" {
+			twelveHour = true
+		}
+	}
+	switch {
+	case firstOver12:
+		return orderDMY
+	case secondOver12:
+		return orderMDY
+	case twelveHour:
+		return orderMDY
+	default:
+		return orderDMY
+	}
+}
+
+func (raw rawMessage) time(order dateOrder, loc *time.Location) (time.Time, error) {
+	var year, month, day int
+	switch order {
+	case orderYMD:
+		year, month, day = raw.date[0], raw.date[1], raw.date[2]
+	case orderMDY:
+		month, day, year = raw.date[0], raw.date[1], raw.date[2]
+	default:
+		day, month, year = raw.date[0], raw.date[1], raw.date[2]
+	}
+	if year < 100 {
+		year += 2000
+	}
+
+	clock := strings.FieldsFunc(raw.clock, func(r rune) bool { return r == ':' || r == '.' })
+	values := make([]int, 3)
+	for i, part := range clock {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", raw.clock)
+		}
+		values[i] = value
+	}
+	hour := values[0]
+	switch raw.meridiem {
+	case "A":
+		if hour == 12 {
+			hour = 0
+		}
+	case "P":
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || values[1] > 59 || values[2] > 59 {
+		return time.Time{}, fmt.Errorf("invalid timestamp %v %s", raw.date, raw.clock)
+	}
+	return time.Date(year, time.Month(month), day, hour, values[1], values[2], 0, loc), nil
+}
+
+// senderCounts counts the "Name: " prefixes of the transcript's messages.
+func senderCounts(raws []rawMessage) map[string]int {
+	counts := map[string]int{}
+	for _, raw := range raws {
+		if sender, _, ok := strings.Cut(raw.body, ": "); ok {
+			counts[sender]++
+		}
+	}
+	return counts
+}
+
+// isSender reports whether prefix, the text before a body's first ": ", is a
+// sender name. Android system messages have no sender but may quote text
+// with a colon ("Alice changed the subject from "Trip" to "Trip: Rome""),
+// so a quoted prefix only counts when it recurs across the chat.
+func isSender(prefix string, senders map[string]int) bool {
+	switch {
+	case strings.Contains(prefix, "\n"):
+		return false
+	case senders[prefix] > 1:
+		return true
+	}
+	return !strings.ContainsAny(prefix, "\"“”„«»")
+}
+
+func parseBody(body string, senders map[string]int) Message {
+	var msg Message
+	sender, text, ok := strings.Cut(body, ": ")
+	if !ok || !isSender(sender, senders) {
+		// Android system messages carry no sender.
+		return Message{System: true, Text: strings.TrimSpace(body)}
+	}
+	msg.Sender = strings.TrimSpace(sender)
+
+	// iOS marks system messages (and attachments) with a left-to-right
+	// mark; without an attachment it is a system notice in the chat's name.
+	marked := strings.HasPrefix(text, "\u200e")
+	text = strings.ReplaceAll(text, "\u200e", "")
+
+	if match := attachedTag.FindStringSubmatch(text); match != nil {
+		msg.Attachment = strings.TrimSpace(match[1])
+		text = strings.TrimSpace(attachedTag.ReplaceAllString(text, ""))
+	} else if match := fileAttached.FindStringSubmatch(text); match != nil {
+		msg.Attachment = strings.TrimSpace(match[1])
+		text = strings.TrimSpace(strings.TrimPrefix(text, match[0]))
+	}
+	for _, marker := range omittedMarkers {
+		if strings.TrimSpace(text) == marker {
+			msg.MediaOmitted = true
+			text = ""
+			break
+		}
+	}
+	if marked && msg.Attachment == "" && !msg.MediaOmitted {
+		msg.System = true
+	}
+	msg.Text = text
+	return msg
+}
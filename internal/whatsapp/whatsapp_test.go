@@ -0,0 +1,128 @@
+package whatsapp
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parse(t *testing.T, transcript string) []Message {
+	t.Helper()
+	messages, err := Parse(strings.NewReader(transcript), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return messages
+}
+
+func TestParseIOS(t *testing.T) {
+	messages := parse(t, "\ufeff[31.12.23, 21:41:05] Family: \u200eMessages and calls are end-to-end encrypted.\n"+
+		"[31.12.23, 21:42:00] Alice: Happy new year\nsecond line\n"+
+		"\u200e[01.01.24, 00:01:10] Bob: \u200e<attached: 00000012-PHOTO-2024-01-01-00-01-10.jpg>\n"+
+		"[01.01.24, 00:02:00] Bob: \u200eimage omitted\n")
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(messages))
+	}
+	if !messages[0].System {
+		t.Fatalf("expected encryption notice to be a system message: %+v", messages[0])
+	}
+	if messages[1].Sender != "Alice" || messages[1].Text != "Happy new year\nsecond line" {
+		t.Fatalf("unexpected multi-line message: %+v", messages[1])
+	}
+	if want := time.Date(2023, 12, 31, 21, 42, 0, 0, time.UTC); !messages[1].Time.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, messages[1].Time)
+	}
+	if messages[2].Attachment != "00000012-PHOTO-2024-01-01-00-01-10.jpg" || messages[2].System {
+		t.Fatalf("unexpected attachment: %+v", messages[2])
+	}
+	if !messages[3].MediaOmitted || messages[3].System {
+		t.Fatalf("expected omitted media: %+v", messages[3])
+	}
+}
+
+func TestParseAndroid12Hour(t *testing.T) {
+	messages := parse(t, "12/31/23, 9:41\u202fPM - Messages and calls are end-to-end encrypted.\n"+
+		"1/1/24, 12:05 AM - Alice: IMG-20240101-WA0001.jpg (file attached)\nlook\n"+
+		"1/1/24, 12:06 PM - Bob: <Media omitted>\n"+
+		"1/2/24, 1:00 PM - Bob: time: noon\n"+
+		"1/2/24, 1:01 PM - Alice changed the subject from \"Trip\" to \"Trip: Rome\"\n")
+	if len(messages) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(messages))
+	}
+	if !messages[0].System || messages[0].Sender != "" {
+		t.Fatalf("expected system message: %+v", messages[0])
+	}
+	if want := time.Date(2023, 12, 31, 21, 41, 0, 0, time.UTC); !messages[0].Time.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, messages[0].Time)
+	}
+	if messages[1].Attachment != "IMG-20240101-WA0001.jpg" || messages[1].Text != "look" || messages[1].Time.Hour() != 0 {
+		t.Fatalf("unexpected attachment message: %+v", messages[1])
+	}
+	if !messages[2].MediaOmitted || messages[2].Time.Hour() != 12 {
+		t.Fatalf("unexpected omitted media: %+v", messages[2])
+	}
+	if messages[3].Sender != "Bob" || messages[3].Text != "time: noon" || messages[3].Time.Day() != 2 {
+		t.Fatalf("unexpected message: %+v", messages[3])
+	}
+	if !messages[4].System || messages[4].Sender != "" || messages[4].Text != `Alice changed the subject from "Trip" to "Trip: Rome"` {
+		t.Fatalf("expected subject change to be a system message: %+v", messages[4])
+	}
+}
+
+func TestParseDayFirstAndISO(t *testing.T) {
+	messages := parse(t, "05/04/2024, 10:00 - Alice: hi\n13/04/2024, 10:00 - Bob: yo\n")
+	if messages[0].Time.Month() != time.April || messages[0].Time.Day() != 5 {
+		t.Fatalf("expected day-first date, got %v", messages[0].Time)
+	}
+
+	messages = parse(t, "2024-04-05, 10:00 - Alice: hi\n")
+	if messages[0].Time.Month() != time.April || messages[0].Time.Day() != 5 {
+		t.Fatalf("expected ISO date, got %v", messages[0].Time)
+	}
+}
+
+func TestParseRejectsUnknownFormat(t *testing.T) {
+	if _, err := Parse(strings.NewReader("not a chat\n"), time.UTC); err == nil {
+		t.Fatalf("expected error for unrecognized transcript")
+	}
+}
+
+func TestOpenZip(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "chat.zip")
+	file, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(file)
+	for name, body := range map[string]string{
+		"WhatsApp Chat with Alice.txt": "1/1/24, 12:05 AM - Alice: IMG-1.jpg (file attached)\n",
+		"IMG-1.jpg":                    "jpeg",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = file.Close()
+
+	export, err := Open(zipPath, time.UTC)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer export.Close()
+	if export.Name != "Alice" || len(export.Messages) != 1 {
+		t.Fatalf("unexpected export: %q %+v", export.Name, export.Messages)
+	}
+	data, err := export.ReadFile(export.Messages[0].Attachment)
+	if err != nil || string(data) != "jpeg" {
+		t.Fatalf("ReadFile: %q %v", data, err)
+	}
+}